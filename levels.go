@@ -90,6 +90,11 @@ func (l *Level) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// MarshalText implements encoding.TextMarshaler.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
 // LevelFunc returns a LogFunc for a level.
 // If the level is invalid it falls back to Info level.
 func LevelFunc(logger Logger, level Level) LogFunc {
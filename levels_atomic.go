@@ -0,0 +1,112 @@
+package logur
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// AtomicLevel is a Level that can be safely read and changed concurrently.
+//
+// It implements LevelEnabler, so a single instance can be shared between several loggers (see WithLevelEnabler)
+// to change their minimum level at runtime.
+// It also implements http.Handler: GET returns the current level, PUT changes it.
+type AtomicLevel struct {
+	level uint32
+}
+
+// NewAtomicLevel returns a new AtomicLevel set to level.
+func NewAtomicLevel(level Level) *AtomicLevel {
+	return &AtomicLevel{level: uint32(level)}
+}
+
+// Level returns the current level.
+func (l *AtomicLevel) Level() Level {
+	return Level(atomic.LoadUint32(&l.level))
+}
+
+// SetLevel changes the current level.
+func (l *AtomicLevel) SetLevel(level Level) {
+	atomic.StoreUint32(&l.level, uint32(level))
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *AtomicLevel) LevelEnabled(level Level) bool {
+	return level >= l.Level()
+}
+
+// String converts the current level to string.
+func (l *AtomicLevel) String() string {
+	return l.Level().String()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *AtomicLevel) UnmarshalText(text []byte) error {
+	var level Level
+
+	if err := level.UnmarshalText(text); err != nil {
+		return err
+	}
+
+	l.SetLevel(level)
+
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l *AtomicLevel) MarshalText() ([]byte, error) {
+	return l.Level().MarshalText()
+}
+
+type atomicLevelPayload struct {
+	Level *Level `json:"level"`
+}
+
+type atomicLevelError struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP implements the http.Handler interface.
+//
+// GET returns the current level as a JSON object: {"level": "info"}
+// PUT accepts the same JSON object and changes the current level.
+func (l *AtomicLevel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+
+	case http.MethodPut:
+		var payload atomicLevelPayload
+
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			writeAtomicLevelError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+
+			return
+		}
+
+		if payload.Level == nil {
+			writeAtomicLevelError(w, http.StatusBadRequest, "level is required")
+
+			return
+		}
+
+		l.SetLevel(*payload.Level)
+
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeAtomicLevelError(w, http.StatusMethodNotAllowed, "only GET and PUT are supported")
+
+		return
+	}
+
+	level := l.Level()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(atomicLevelPayload{Level: &level})
+}
+
+func writeAtomicLevelError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(atomicLevelError{Error: msg})
+}
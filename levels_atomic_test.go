@@ -0,0 +1,130 @@
+package logur_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+func TestAtomicLevel(t *testing.T) {
+	t.Run("SetLevel", func(t *testing.T) {
+		level := NewAtomicLevel(Info)
+
+		if level.LevelEnabled(Debug) {
+			t.Error("debug level should be disabled")
+		}
+
+		level.SetLevel(Debug)
+
+		if !level.LevelEnabled(Debug) {
+			t.Error("debug level should be enabled")
+		}
+
+		if want, have := Debug, level.Level(); want != have {
+			t.Errorf("unexpected level\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		level := NewAtomicLevel(Info)
+		logger := WithLevelEnabler(&TestLoggerFacade{}, level)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				level.SetLevel(Debug)
+				level.SetLevel(Info)
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				logger.Debug("message")
+			}()
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("Shared", func(t *testing.T) {
+		level := NewAtomicLevel(Error)
+
+		logger1 := &TestLoggerFacade{}
+		logger2 := &TestLogger{}
+
+		l1 := WithLevelEnabler(logger1, level)
+		l2 := WithLevelEnabler(logger2, level)
+
+		l1.Info("message")
+		l2.Info("message")
+
+		level.SetLevel(Info)
+
+		l1.Info("message")
+		l2.Info("message")
+
+		if logger1.Count() != 1 || logger2.Count() != 1 {
+			t.Errorf("both loggers should record exactly one event: %d, %d", logger1.Count(), logger2.Count())
+		}
+	})
+
+	t.Run("HTTP", func(t *testing.T) {
+		level := NewAtomicLevel(Info)
+
+		tests := []struct {
+			method       string
+			body         string
+			expectedCode int
+			expectedBody string
+			level        Level
+		}{
+			{http.MethodGet, "", http.StatusOK, `{"level":"info"}`, Info},
+			{http.MethodPut, `{"level":"debug"}`, http.StatusOK, `{"level":"debug"}`, Debug},
+			{http.MethodGet, "", http.StatusOK, `{"level":"debug"}`, Debug},
+			{http.MethodPut, `{"level":"invalid"}`, http.StatusBadRequest, "", Debug},
+			{http.MethodPut, `{}`, http.StatusBadRequest, `{"error":"level is required"}`, Debug},
+			{http.MethodPost, `{"level":"error"}`, http.StatusMethodNotAllowed, "", Debug},
+		}
+
+		for _, test := range tests {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(test.method, "/level", strings.NewReader(test.body))
+
+			level.ServeHTTP(w, r)
+
+			if want, have := test.expectedCode, w.Code; want != have {
+				t.Errorf("%s %s: unexpected status code\nexpected: %d\nactual:   %d", test.method, test.body, want, have)
+			}
+
+			if body := strings.TrimSpace(w.Body.String()); test.expectedBody != "" && body != test.expectedBody {
+				t.Errorf("%s %s: unexpected body\nexpected: %s\nactual:   %s", test.method, test.body, test.expectedBody, body)
+			}
+
+			if want, have := test.level, level.Level(); want != have {
+				t.Errorf("%s %s: unexpected level\nexpected: %s\nactual:   %s", test.method, test.body, want, have)
+			}
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				logger := &TestLoggerFacade{}
+
+				return WithLevelEnabler(logger, NewAtomicLevel(level)), logger
+			},
+		}
+
+		suite.Run(t)
+	})
+}
@@ -62,6 +62,17 @@ func TestParseAndUnmarshalLevel(t *testing.T) {
 				t.Errorf("unmarshaled level %q does not match the expected %q", l, level)
 			}
 		})
+
+		t.Run("marshal:"+levelName, func(t *testing.T) {
+			text, err := level.MarshalText()
+			if err != nil {
+				t.Fatal("marshaling level failed:", err.Error())
+			}
+
+			if string(text) != levelName {
+				t.Errorf("marshaled level %q does not match the expected %q", string(text), levelName)
+			}
+		})
 	}
 }
 
@@ -0,0 +1,391 @@
+package logur
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what happens to log events when the queue of an AsyncLogger is full.
+type OverflowPolicy int
+
+// Overflow policies supported by AsyncLogger.
+const (
+	// OverflowBlock blocks the caller until there is room in the queue.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest drops the event being logged.
+	OverflowDropNewest
+
+	// OverflowDropOldest drops the oldest event in the queue to make room for the new one.
+	OverflowDropOldest
+
+	// OverflowDropBelowLevel drops events below AsyncLoggerConfig.DropLevel and blocks for the rest.
+	OverflowDropBelowLevel
+)
+
+// AsyncLoggerConfig configures an AsyncLogger.
+type AsyncLoggerConfig struct {
+	// QueueSize is the maximum number of events waiting to be logged (defaults to 1024).
+	QueueSize int
+
+	// OverflowPolicy decides what happens when the queue is full (defaults to OverflowBlock).
+	OverflowPolicy OverflowPolicy
+
+	// DropLevel is the level below which events are dropped when the policy is OverflowDropBelowLevel.
+	DropLevel Level
+}
+
+// ErrAsyncLoggerClosed is returned when an already closed AsyncLogger is flushed.
+var ErrAsyncLoggerClosed = errors.New("async logger is closed")
+
+// AsyncLogger passes log events to a logger on a separate goroutine through a bounded queue,
+// so that slow loggers do not block the caller.
+//
+// Fields are copied before they are queued, but the values themselves are not,
+// so they should not be mutated after the event is logged.
+// Contexts are passed to the underlying logger as they are, even if they are canceled by then.
+//
+// Call Close to drain the queue and stop the worker goroutine.
+type AsyncLogger struct {
+	// dropped counters are accessed atomically, keep them at the top for 64-bit alignment
+	dropped        uint64
+	droppedByLevel [5]uint64
+
+	logger       LoggerFacade
+	levelEnabler LevelEnabler
+	config       AsyncLoggerConfig
+
+	queue   chan asyncEvent
+	closing chan struct{}
+	done    chan struct{}
+
+	// mu guards closed, it is never held while waiting for room in the queue
+	mu     sync.RWMutex
+	closed bool
+
+	// senders tracks callers that may still put events into the queue
+	senders sync.WaitGroup
+}
+
+type asyncEvent struct {
+	ctx    context.Context
+	level  Level
+	msg    string
+	fields map[string]interface{}
+
+	// flushed is closed when the event is processed (only set for flush markers)
+	flushed chan struct{}
+}
+
+// NewAsyncLogger returns a new AsyncLogger and starts its worker goroutine.
+func NewAsyncLogger(logger Logger, config AsyncLoggerConfig) *AsyncLogger {
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1024
+	}
+
+	l := &AsyncLogger{
+		logger:  ensureLoggerFacade(logger),
+		config:  config,
+		queue:   make(chan asyncEvent, config.QueueSize),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if levelEnabler, ok := logger.(LevelEnabler); ok {
+		l.levelEnabler = levelEnabler
+	}
+
+	go l.run()
+
+	return l
+}
+
+func (l *AsyncLogger) run() {
+	defer close(l.done)
+
+	for {
+		select {
+		case event := <-l.queue:
+			l.process(event)
+
+		case <-l.closing:
+			// Wait for blocked callers to give up, then drain the queue
+			l.senders.Wait()
+
+			for {
+				select {
+				case event := <-l.queue:
+					l.process(event)
+
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l *AsyncLogger) process(event asyncEvent) {
+	if event.flushed != nil {
+		close(event.flushed)
+
+		return
+	}
+
+	if event.ctx != nil {
+		LevelContextFunc(l.logger, event.level)(event.ctx, event.msg, event.fields)
+	} else {
+		LevelFunc(l.logger, event.level)(event.msg, event.fields)
+	}
+}
+
+// Trace implements the Logger interface.
+func (l *AsyncLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *AsyncLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *AsyncLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *AsyncLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *AsyncLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Error, msg, fields)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *AsyncLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *AsyncLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *AsyncLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *AsyncLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *AsyncLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Error, msg, fields)
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *AsyncLogger) LevelEnabled(level Level) bool {
+	if l.levelEnabler != nil {
+		return l.levelEnabler.LevelEnabled(level)
+	}
+
+	return true
+}
+
+func (l *AsyncLogger) log(ctx context.Context, level Level, msg string, fields []map[string]interface{}) {
+	if !l.LevelEnabled(level) {
+		return
+	}
+
+	event := asyncEvent{
+		ctx:   ctx,
+		level: level,
+		msg:   msg,
+	}
+
+	if len(fields) > 0 && len(fields[0]) > 0 {
+		event.fields = make(map[string]interface{}, len(fields[0]))
+
+		for key, value := range fields[0] {
+			event.fields[key] = value
+		}
+	}
+
+	if !l.startSending() {
+		l.drop(level)
+
+		return
+	}
+	defer l.senders.Done()
+
+	l.enqueue(event)
+}
+
+// startSending registers a caller putting events into the queue unless the logger is closed.
+func (l *AsyncLogger) startSending() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		return false
+	}
+
+	l.senders.Add(1)
+
+	return true
+}
+
+// send waits for room in the queue, dropping the event if the logger is closed in the meantime.
+func (l *AsyncLogger) send(event asyncEvent) {
+	select {
+	case l.queue <- event:
+	case <-l.closing:
+		l.drop(event.level)
+	}
+}
+
+func (l *AsyncLogger) enqueue(event asyncEvent) {
+	select {
+	case l.queue <- event:
+		return
+
+	default:
+	}
+
+	switch l.config.OverflowPolicy {
+	case OverflowDropNewest:
+		l.drop(event.level)
+
+	case OverflowDropOldest:
+		for {
+			// Prefer queueing the event if there is room
+			select {
+			case l.queue <- event:
+				return
+
+			default:
+			}
+
+			select {
+			case l.queue <- event:
+				return
+
+			case oldest := <-l.queue:
+				// Flush markers are never dropped, put them back to the end of the queue
+				if oldest.flushed != nil {
+					select {
+					case l.queue <- oldest:
+					case <-l.closing:
+						// Flush waits for the worker to finish in this case
+						l.drop(event.level)
+
+						return
+					}
+
+					continue
+				}
+
+				l.drop(oldest.level)
+			}
+		}
+
+	case OverflowDropBelowLevel:
+		if event.level < l.config.DropLevel {
+			l.drop(event.level)
+
+			return
+		}
+
+		l.send(event)
+
+	default:
+		l.send(event)
+	}
+}
+
+func (l *AsyncLogger) drop(level Level) {
+	atomic.AddUint64(&l.dropped, 1)
+
+	if int(level) < len(l.droppedByLevel) {
+		atomic.AddUint64(&l.droppedByLevel[level], 1)
+	}
+}
+
+// Dropped returns the number of dropped events.
+func (l *AsyncLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// DroppedByLevel returns the number of dropped events for a level.
+func (l *AsyncLogger) DroppedByLevel(level Level) uint64 {
+	if int(level) >= len(l.droppedByLevel) {
+		return 0
+	}
+
+	return atomic.LoadUint64(&l.droppedByLevel[level])
+}
+
+// Flush waits until every event queued before the call is passed to the underlying logger
+// or the context is canceled.
+func (l *AsyncLogger) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+
+	if !l.startSending() {
+		return ErrAsyncLoggerClosed
+	}
+
+	select {
+	case l.queue <- asyncEvent{flushed: flushed}:
+		l.senders.Done()
+
+	case <-l.closing:
+		l.senders.Done()
+
+		return ErrAsyncLoggerClosed
+
+	case <-ctx.Done():
+		l.senders.Done()
+
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		return nil
+
+	case <-l.done:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting new events and waits until the queue is drained or the context is canceled.
+// Events logged after Close are dropped.
+func (l *AsyncLogger) Close(ctx context.Context) error {
+	l.mu.Lock()
+
+	if !l.closed {
+		l.closed = true
+		close(l.closing)
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,267 @@
+package logur_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+// gateLogger blocks every log event until the gate is opened.
+type gateLogger struct {
+	TestLoggerFacade
+
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func newGateLogger() *gateLogger {
+	return &gateLogger{
+		started: make(chan struct{}, 100),
+		gate:    make(chan struct{}),
+	}
+}
+
+func (l *gateLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.started <- struct{}{}
+	<-l.gate
+
+	l.TestLoggerFacade.Info(msg, fields...)
+}
+
+func logMessages(logger Logger, msgs ...string) {
+	for _, msg := range msgs {
+		logger.Info(msg)
+	}
+}
+
+func eventLines(events []LogEvent) []string {
+	lines := make([]string, 0, len(events))
+
+	for _, event := range events {
+		lines = append(lines, event.Line)
+	}
+
+	return lines
+}
+
+func TestAsyncLogger(t *testing.T) {
+	t.Run("Flush", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := NewAsyncLogger(testLogger, AsyncLoggerConfig{})
+		defer logger.Close(context.Background())
+
+		logger.Info("message", map[string]interface{}{"key": "value"})
+		logger.ErrorContext(context.Background(), "error")
+
+		if err := logger.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, have := 2, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("CopyFields", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := NewAsyncLogger(testLogger, AsyncLoggerConfig{})
+		defer logger.Close(context.Background())
+
+		fields := map[string]interface{}{"key": "value"}
+
+		logger.Info("message", fields)
+		fields["key"] = "changed"
+
+		_ = logger.Flush(context.Background())
+
+		if want, have := "value", testLogger.LastEvent().Fields["key"]; want != have {
+			t.Errorf("unexpected field value\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	policyTests := map[string]struct {
+		config   AsyncLoggerConfig
+		expected []string
+		dropped  uint64
+	}{
+		"DropNewest": {
+			config:   AsyncLoggerConfig{QueueSize: 2, OverflowPolicy: OverflowDropNewest},
+			expected: []string{"0", "1", "2"},
+			dropped:  2,
+		},
+		"DropOldest": {
+			config:   AsyncLoggerConfig{QueueSize: 2, OverflowPolicy: OverflowDropOldest},
+			expected: []string{"0", "3", "4"},
+			dropped:  2,
+		},
+	}
+
+	for name, test := range policyTests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			testLogger := newGateLogger()
+
+			logger := NewAsyncLogger(testLogger, test.config)
+
+			// The first event is picked up by the worker and blocks it
+			logger.Info("0")
+			<-testLogger.started
+
+			logMessages(logger, "1", "2", "3", "4")
+
+			close(testLogger.gate)
+
+			if err := logger.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := eventLines(testLogger.Events())
+
+			if want, have := test.expected, lines; len(want) != len(have) || want[1] != have[1] || want[2] != have[2] {
+				t.Errorf("unexpected events\nexpected: %v\nactual:   %v", want, have)
+			}
+
+			if want, have := test.dropped, logger.Dropped(); want != have {
+				t.Errorf("unexpected number of dropped events\nexpected: %d\nactual:   %d", want, have)
+			}
+
+			if want, have := test.dropped, logger.DroppedByLevel(Info); want != have {
+				t.Errorf("unexpected number of dropped info events\nexpected: %d\nactual:   %d", want, have)
+			}
+		})
+	}
+
+	t.Run("DropBelowLevel", func(t *testing.T) {
+		testLogger := newGateLogger()
+
+		logger := NewAsyncLogger(testLogger, AsyncLoggerConfig{
+			QueueSize:      1,
+			OverflowPolicy: OverflowDropBelowLevel,
+			DropLevel:      Warn,
+		})
+
+		logger.Info("0")
+		<-testLogger.started
+
+		logger.Info("1")
+		logger.Info("2")
+		logger.Debug("3")
+
+		if want, have := uint64(2), logger.Dropped(); want != have {
+			t.Errorf("unexpected number of dropped events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if want, have := uint64(1), logger.DroppedByLevel(Debug); want != have {
+			t.Errorf("unexpected number of dropped debug events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		close(testLogger.gate)
+
+		_ = logger.Close(context.Background())
+	})
+
+	t.Run("CloseDeadline", func(t *testing.T) {
+		testLogger := newGateLogger()
+
+		logger := NewAsyncLogger(testLogger, AsyncLoggerConfig{})
+
+		logger.Info("message")
+		<-testLogger.started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if err := logger.Close(ctx); err != context.DeadlineExceeded {
+			t.Errorf("close is expected to time out, got: %v", err)
+		}
+
+		logger.Info("dropped")
+
+		if want, have := uint64(1), logger.Dropped(); want != have {
+			t.Errorf("unexpected number of dropped events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if err := logger.Flush(context.Background()); err != ErrAsyncLoggerClosed {
+			t.Errorf("flush is expected to fail after close, got: %v", err)
+		}
+
+		close(testLogger.gate)
+
+		if err := logger.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("CloseDeadlineBlockedCaller", func(t *testing.T) {
+		testLogger := newGateLogger()
+
+		logger := NewAsyncLogger(testLogger, AsyncLoggerConfig{QueueSize: 1})
+
+		logger.Info("message 1")
+		<-testLogger.started
+
+		logger.Info("message 2")
+
+		blocked := make(chan struct{})
+
+		go func() {
+			defer close(blocked)
+
+			logger.Info("message 3")
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+
+		if err := logger.Close(ctx); err != context.DeadlineExceeded {
+			t.Errorf("close is expected to time out, got: %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("close is expected to respect the deadline, took %s", elapsed)
+		}
+
+		select {
+		case <-blocked:
+		case <-time.After(5 * time.Second):
+			t.Fatal("blocked caller is expected to return after close")
+		}
+
+		close(testLogger.gate)
+
+		if err := logger.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, have := []string{"message 1", "message 2"}, eventLines(testLogger.Events()); !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected events\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				testLogger := &TestLoggerFacade{}
+
+				logger := NewAsyncLogger(WithMinLevel(testLogger, level), AsyncLoggerConfig{})
+
+				return logger, conformance.TestLoggerFunc(func() []LogEvent {
+					_ = logger.Close(context.Background())
+
+					return testLogger.Events()
+				})
+			},
+		}
+
+		suite.Run(t)
+	})
+}
@@ -0,0 +1,99 @@
+package logur
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// logurPackagePrefix is the prefix of function names in this package.
+const logurPackagePrefix = "logur.dev/logur."
+
+// CallerOptions configures caller annotation.
+type CallerOptions struct {
+	// Function adds the name of the calling function as a "function" field.
+	Function bool
+
+	// FullPath makes the "caller" field contain the full path of the file
+	// (instead of the last directory and the file name).
+	FullPath bool
+
+	// CallerSkip is the number of additional frames to skip (eg. for logging helpers in user code).
+	CallerSkip int
+}
+
+// WithCaller returns a new logger that annotates every log event with the location (file:line) of the log call
+// as a "caller" field.
+//
+// Frames of this package are skipped, no matter how many loggers of this package are stacked on top of each other.
+// Loggers passing events to another goroutine (eg. AsyncLogger) should be wrapped by this logger, not the other way.
+//
+// The returned logger also implements LevelEnabler.
+func WithCaller(logger Logger, opts CallerOptions) LoggerFacade {
+	return WithProcessors(logger, CallerProcessor(opts))
+}
+
+// CallerProcessor returns a Processor that annotates every log event with the location of the log call.
+// See WithCaller for details.
+func CallerProcessor(opts CallerOptions) Processor {
+	return func(_ context.Context, record *Record) bool {
+		frame, ok := callerFrame(opts.CallerSkip)
+		if !ok {
+			return true
+		}
+
+		file := frame.File
+		if !opts.FullPath {
+			file = shortCallerPath(file)
+		}
+
+		record.SetField("caller", file+":"+strconv.Itoa(frame.Line))
+
+		if opts.Function {
+			record.SetField("function", frame.Function)
+		}
+
+		return true
+	}
+}
+
+// callerFrame returns the first frame outside of this package (skipping skip additional frames).
+func callerFrame(skip int) (runtime.Frame, bool) {
+	var pcs [64]uintptr
+
+	// Skip runtime.Callers and callerFrame
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+
+		if !strings.HasPrefix(frame.Function, logurPackagePrefix) {
+			if skip == 0 {
+				return frame, true
+			}
+
+			skip--
+		}
+
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// shortCallerPath trims a file path to the last directory and the file name.
+func shortCallerPath(file string) string {
+	i := strings.LastIndexByte(file, '/')
+	if i < 0 {
+		return file
+	}
+
+	j := strings.LastIndexByte(file[:i], '/')
+	if j < 0 {
+		return file
+	}
+
+	return file[j+1:]
+}
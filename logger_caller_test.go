@@ -0,0 +1,126 @@
+package logur_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+// currentLine returns the file:line of its caller.
+func currentLine(t *testing.T, offset int) string {
+	t.Helper()
+
+	_, file, line, _ := runtime.Caller(1)
+
+	dir, name := filepath.Split(file)
+
+	return fmt.Sprintf("%s/%s:%d", filepath.Base(dir), name, line+offset)
+}
+
+func logHelper(logger Logger, msg string) {
+	logger.Info(msg)
+}
+
+func TestWithCaller(t *testing.T) {
+	t.Run("Stacked", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithFields(
+			WithCaller(
+				WithFields(
+					WithContextExtractor(testLogger, func(_ context.Context) map[string]interface{} { return nil }),
+					map[string]interface{}{"key": "value"},
+				),
+				CallerOptions{Function: true},
+			),
+			map[string]interface{}{"key2": "value2"},
+		)
+
+		logger.Info("message")
+		expected := currentLine(t, -1)
+
+		LevelContextFunc(logger, Warn)(context.Background(), "message")
+		expectedContext := currentLine(t, -1)
+
+		events := testLogger.Events()
+
+		if want, have := expected, events[0].Fields["caller"]; want != have {
+			t.Errorf("unexpected caller\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := expectedContext, events[1].Fields["caller"]; want != have {
+			t.Errorf("unexpected caller\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := "logur.dev/logur_test.TestWithCaller.func1", events[0].Fields["function"]; want != have {
+			t.Errorf("unexpected function\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := "value", events[0].Fields["key"]; want != have {
+			t.Errorf("unexpected field value\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("CallerSkip", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithCaller(testLogger, CallerOptions{CallerSkip: 1, FullPath: true})
+
+		logHelper(logger, "message")
+		expected := currentLine(t, -1)
+
+		caller := testLogger.LastEvent().Fields["caller"].(string)
+
+		if !strings.HasPrefix(caller, "/") || !strings.HasSuffix(caller, expected) {
+			t.Errorf("unexpected caller\nexpected: %v\nactual:   %v", expected, caller)
+		}
+
+		if _, ok := testLogger.LastEvent().Fields["function"]; ok {
+			t.Error("function field should not be added")
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				testLogger := &TestLoggerFacade{}
+
+				return WithCaller(WithMinLevel(testLogger, level), CallerOptions{}), conformance.TestLoggerFunc(
+					func() []LogEvent {
+						var events []LogEvent
+
+						for _, event := range testLogger.Events() {
+							event.Fields = copyWithout(event.Fields, "caller")
+							events = append(events, event)
+						}
+
+						return events
+					},
+				)
+			},
+		}
+
+		suite.Run(t)
+	})
+}
+
+// copyWithout returns a copy of the fields without the given keys.
+func copyWithout(fields map[string]interface{}, keys ...string) map[string]interface{} {
+	f := make(map[string]interface{}, len(fields))
+
+	for key, value := range fields {
+		f[key] = value
+	}
+
+	for _, key := range keys {
+		delete(f, key)
+	}
+
+	return f
+}
@@ -0,0 +1,165 @@
+package logur
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"time"
+)
+
+// ColorMode controls colorized output of the console logger.
+type ColorMode int
+
+// Color modes supported by the console logger.
+const (
+	// ColorAuto enables colors when the output is a terminal and NO_COLOR is not set.
+	ColorAuto ColorMode = iota
+
+	// ColorAlways enables colors unconditionally.
+	ColorAlways
+
+	// ColorNever disables colors unconditionally.
+	ColorNever
+)
+
+// Colors sets the color mode of the console logger.
+func Colors(mode ColorMode) OutputOption {
+	return func(o *outputOptions) {
+		o.colorMode = mode
+	}
+}
+
+// RelativeTime makes the console logger print the time elapsed since the logger was created
+// instead of the wall clock time.
+func RelativeTime() OutputOption {
+	return func(o *outputOptions) {
+		o.relativeTime = true
+	}
+}
+
+// NewConsoleLogger returns a new human-readable logger for local development.
+//
+// Every line starts with an aligned level badge (colored by level), followed by the message and the fields
+// in dimmed key=value format. Colors are disabled by default when w is not a terminal
+// or when the NO_COLOR environment variable is set (see https://no-color.org).
+//
+// The returned logger also implements LevelEnabler.
+func NewConsoleLogger(w io.Writer, opts ...OutputOption) LoggerFacade {
+	l := newOutputLogger(w, nil, append([]OutputOption{TimeLayout("15:04:05.000")}, opts...))
+
+	l.encoder = consoleEncoder{
+		color: consoleColorEnabled(w, l.options.colorMode),
+		start: l.options.now(),
+	}
+
+	return l
+}
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiDim   = "\x1b[2m"
+)
+
+// nolint: gochecknoglobals
+var consoleLevelBadges = map[Level]struct {
+	text  string
+	color string
+}{
+	Trace: {"TRACE", "\x1b[35m"},
+	Debug: {"DEBUG", "\x1b[36m"},
+	Info:  {"INFO ", "\x1b[32m"},
+	Warn:  {"WARN ", "\x1b[33m"},
+	Error: {"ERROR", "\x1b[31m"},
+}
+
+type consoleEncoder struct {
+	color bool
+	start time.Time
+}
+
+func (e consoleEncoder) encode(buf *bytes.Buffer, event outputEvent, options outputOptions) {
+	if options.timeKey != "" {
+		e.startColor(buf, ansiDim)
+
+		if options.relativeTime {
+			elapsed := event.time.Sub(e.start).Truncate(time.Millisecond)
+			buf.WriteString("+")
+			buf.WriteString(elapsed.String())
+		} else {
+			buf.WriteString(event.time.Format(options.timeLayout))
+		}
+
+		e.endColor(buf)
+		buf.WriteByte(' ')
+	}
+
+	badge, ok := consoleLevelBadges[event.level]
+	if !ok {
+		badge.text = "?????"
+	}
+
+	e.startColor(buf, badge.color)
+	buf.WriteString(badge.text)
+	e.endColor(buf)
+
+	buf.WriteByte(' ')
+	buf.WriteString(event.msg)
+
+	if len(event.fields) > 0 {
+		e.startColor(buf, ansiDim)
+
+		for _, key := range sortedKeys(event.fields) {
+			buf.WriteByte(' ')
+			writeLogfmtKeyValue(buf, key, logfmtValue(event.fields[key]))
+		}
+
+		e.endColor(buf)
+	}
+
+	buf.WriteByte('\n')
+}
+
+func (e consoleEncoder) startColor(buf *bytes.Buffer, color string) {
+	if e.color && color != "" {
+		buf.WriteString(color)
+	}
+}
+
+func (e consoleEncoder) endColor(buf *bytes.Buffer) {
+	if e.color {
+		buf.WriteString(ansiReset)
+	}
+}
+
+// consoleColorEnabled decides whether colors should be used for an output.
+func consoleColorEnabled(w io.Writer, mode ColorMode) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return isTerminal(w)
+}
+
+// isTerminal checks if a writer is a terminal.
+// It only recognizes character devices, so it might give false positives (eg. for /dev/null).
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
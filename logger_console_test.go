@@ -0,0 +1,101 @@
+package logur_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+func TestConsoleLogger(t *testing.T) {
+	t.Run("NoColor", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := NewConsoleLogger(&buf, TimeKey(""))
+
+		logger.Info("message", map[string]interface{}{"key2": "value 2", "key1": 1})
+		logger.Error("error")
+
+		const expected = "INFO  message key1=1 key2=\"value 2\"\nERROR error\n"
+
+		if want, have := expected, buf.String(); want != have {
+			t.Errorf("unexpected output\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("Color", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := NewConsoleLogger(&buf, TimeKey(""), Colors(ColorAlways))
+
+		logger.Warn("message", map[string]interface{}{"key": "value"})
+
+		const expected = "\x1b[33mWARN \x1b[0m message\x1b[2m key=value\x1b[0m\n"
+
+		if want, have := expected, buf.String(); want != have {
+			t.Errorf("unexpected output\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("AutoDetect", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		// Buffers are never terminals
+		NewConsoleLogger(&buf, TimeKey(""), Colors(ColorAuto)).Info("message")
+
+		if want, have := "INFO  message\n", buf.String(); want != have {
+			t.Errorf("unexpected output\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("RelativeTime", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := NewConsoleLogger(&buf, RelativeTime())
+
+		logger.Info("message")
+
+		if !regexp.MustCompile(`^\+\d+(\.\d+)?[mµn]?s INFO  message\n$`).Match(buf.Bytes()) {
+			t.Errorf("unexpected output: %q", buf.String())
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				var buf bytes.Buffer
+
+				logger := NewConsoleLogger(&buf, TimeKey(""), Colors(ColorNever), MinLevel(level))
+
+				return logger, conformance.TestLoggerFunc(func() []LogEvent {
+					var events []LogEvent
+
+					for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+						if line == "" {
+							continue
+						}
+
+						level, _ := ParseLevel(strings.TrimSpace(line[:5]))
+						parts := strings.SplitN(line[6:], " ", 2)
+						event := LogEvent{Line: parts[0], Level: level, Fields: map[string]interface{}{}}
+
+						if len(parts) > 1 {
+							for key, value := range parseLogfmtLine(t, parts[1]) {
+								event.Fields[key] = value
+							}
+						}
+
+						events = append(events, event)
+					}
+
+					return events
+				})
+			},
+		}
+
+		suite.Run(t)
+	})
+}
@@ -0,0 +1,206 @@
+package logur
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DedupLoggerConfig configures a DedupLogger.
+type DedupLoggerConfig struct {
+	// FlushInterval is the maximum time repeated events are held back for.
+	// Zero means repeated events are only reported when the run of events ends (or on Flush).
+	FlushInterval time.Duration
+}
+
+// DedupLogger collapses consecutive log events with the same level, message and fields.
+//
+// The first event of a run is logged immediately, the repeated ones are held back.
+// When the run ends (a different event is logged) or the flush interval passes,
+// a single follow-up event is logged with the original level, message and fields,
+// extended with the number of repeated events ("repeated") and their first and last timestamps
+// ("repeated_first" and "repeated_last").
+type DedupLogger struct {
+	logger       LoggerFacade
+	levelEnabler LevelEnabler
+	config       DedupLoggerConfig
+	now          func() time.Time
+
+	mu    sync.Mutex
+	run   *dedupRun
+	timer *time.Timer
+}
+
+// dedupRun is a series of identical log events.
+type dedupRun struct {
+	ctx    context.Context
+	level  Level
+	msg    string
+	fields map[string]interface{}
+
+	repeated int
+	first    time.Time
+	last     time.Time
+}
+
+// NewDedupLogger returns a new DedupLogger.
+func NewDedupLogger(logger Logger, config DedupLoggerConfig) *DedupLogger {
+	l := &DedupLogger{
+		logger: ensureLoggerFacade(logger),
+		config: config,
+		now:    time.Now,
+	}
+
+	if levelEnabler, ok := logger.(LevelEnabler); ok {
+		l.levelEnabler = levelEnabler
+	}
+
+	return l
+}
+
+// Trace implements the Logger interface.
+func (l *DedupLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *DedupLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *DedupLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *DedupLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *DedupLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Error, msg, fields)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *DedupLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *DedupLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *DedupLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *DedupLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *DedupLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Error, msg, fields)
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *DedupLogger) LevelEnabled(level Level) bool {
+	if l.levelEnabler != nil {
+		return l.levelEnabler.LevelEnabled(level)
+	}
+
+	return true
+}
+
+// Flush logs the number of repeated events held back (if any).
+func (l *DedupLogger) Flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.flush()
+}
+
+func (l *DedupLogger) log(ctx context.Context, level Level, msg string, fields []map[string]interface{}) {
+	if !l.LevelEnabled(level) {
+		return
+	}
+
+	var f map[string]interface{}
+	if len(fields) > 0 {
+		f = fields[0]
+	}
+
+	now := l.now()
+
+	// Events are logged while holding the lock to keep them in order
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if run := l.run; run != nil && run.level == level && run.msg == msg && fieldsEqual(run.fields, f) {
+		if run.repeated == 0 {
+			run.first = now
+
+			if l.config.FlushInterval > 0 {
+				l.timer = time.AfterFunc(l.config.FlushInterval, l.Flush)
+			}
+		}
+
+		run.repeated++
+		run.last = now
+
+		return
+	}
+
+	l.flush()
+
+	l.run = &dedupRun{
+		ctx:    ctx,
+		level:  level,
+		msg:    msg,
+		fields: copyFields(f),
+	}
+
+	l.write(ctx, level, msg, f)
+}
+
+// flush logs the number of repeated events of the current run.
+// It must be called while holding the lock.
+func (l *DedupLogger) flush() {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+
+	run := l.run
+	if run == nil || run.repeated == 0 {
+		return
+	}
+
+	fields := mergeFields(
+		Fields{
+			"repeated":       run.repeated,
+			"repeated_first": run.first,
+			"repeated_last":  run.last,
+		},
+		[]map[string]interface{}{run.fields},
+	)
+
+	run.repeated = 0
+
+	l.write(run.ctx, run.level, run.msg, fields)
+}
+
+func (l *DedupLogger) write(ctx context.Context, level Level, msg string, fields map[string]interface{}) {
+	if ctx != nil {
+		LevelContextFunc(l.logger, level)(ctx, msg, fields)
+
+		return
+	}
+
+	LevelFunc(l.logger, level)(msg, fields)
+}
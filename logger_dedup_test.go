@@ -0,0 +1,130 @@
+package logur_test
+
+import (
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+func TestDedupLogger(t *testing.T) {
+	t.Run("RunEnds", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := NewDedupLogger(testLogger, DedupLoggerConfig{})
+
+		fields := map[string]interface{}{"key": "value", "slice": []string{"a"}}
+
+		for i := 0; i < 5; i++ {
+			logger.Warn("message", fields)
+		}
+
+		logger.Warn("message", map[string]interface{}{"key": "other value"})
+
+		events := testLogger.Events()
+
+		if want, have := 3, len(events); want != have {
+			t.Fatalf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		summary := events[1]
+
+		if summary.Line != "message" || summary.Level != Warn || summary.Fields["key"] != "value" {
+			t.Errorf("unexpected follow-up event: %+v", summary)
+		}
+
+		if want, have := 4, summary.Fields["repeated"]; want != have {
+			t.Errorf("unexpected repeat count\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		first := summary.Fields["repeated_first"].(time.Time)
+		last := summary.Fields["repeated_last"].(time.Time)
+
+		if last.Before(first) {
+			t.Errorf("last timestamp (%s) is before the first one (%s)", last, first)
+		}
+	})
+
+	t.Run("MutatedFields", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := NewDedupLogger(testLogger, DedupLoggerConfig{})
+
+		fields := map[string]interface{}{}
+
+		for i := 0; i < 3; i++ {
+			fields["i"] = i
+			logger.Info("message", fields)
+		}
+
+		if want, have := 3, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("UncomparableFields", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := NewDedupLogger(testLogger, DedupLoggerConfig{})
+
+		for i := 0; i < 3; i++ {
+			logger.Info("message", map[string]interface{}{"x": struct{ V interface{} }{[]int{1}}})
+		}
+
+		logger.Flush()
+
+		if want, have := 2, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("Flush", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := NewDedupLogger(testLogger, DedupLoggerConfig{})
+
+		logger.Info("message")
+		logger.Info("message")
+		logger.Flush()
+		logger.Flush()
+
+		if want, have := 2, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("FlushInterval", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := NewDedupLogger(testLogger, DedupLoggerConfig{FlushInterval: 10 * time.Millisecond})
+
+		logger.Info("message")
+		logger.Info("message")
+		logger.Info("message")
+
+		for i := 0; i < 10 && testLogger.Count() < 2; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		if want, have := 2, testLogger.Count(); want != have {
+			t.Fatalf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if want, have := 2, testLogger.LastEvent().Fields["repeated"]; want != have {
+			t.Errorf("unexpected repeat count\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				logger := &TestLoggerFacade{}
+
+				return NewDedupLogger(WithMinLevel(logger, level), DedupLoggerConfig{}), logger
+			},
+		}
+
+		suite.Run(t)
+	})
+}
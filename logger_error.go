@@ -0,0 +1,155 @@
+package logur
+
+import (
+	"context"
+	"fmt"
+)
+
+// ErrorExpansionOptions configures the expansion of error fields.
+//
+// Every error valued field is replaced by its message and additional fields are added using the key of the field
+// and a suffix (eg. "error.type" for an "error" field).
+type ErrorExpansionOptions struct {
+	// TypeSuffix is appended to the key of the field holding the error type (defaults to ".type").
+	TypeSuffix string
+
+	// ChainSuffix is appended to the key of the field holding the messages of wrapped errors (defaults to ".chain").
+	ChainSuffix string
+
+	// DetailsSeparator separates the key of the error field and the keys of details
+	// exposed by errors (defaults to ".").
+	DetailsSeparator string
+
+	// OmitType disables adding the error type.
+	OmitType bool
+
+	// OmitChain disables adding the messages of wrapped errors.
+	OmitChain bool
+
+	// OmitDetails disables adding details exposed by errors.
+	OmitDetails bool
+
+	// MaxChainDepth is the maximum number of wrapped errors followed (defaults to 32).
+	MaxChainDepth int
+}
+
+// WithErrorExpansion returns a new logger that expands error valued fields.
+//
+// An error field is replaced by the message of the error and the following fields are added:
+//   - the type of the error (eg. "error.type")
+//   - the messages of the wrapped errors, following Unwrap (and Cause) methods (eg. "error.chain")
+//   - details exposed by any error in the chain through a Fields() map[string]interface{} method (eg. "error.code")
+//
+// Fields of the event take precedence over the added ones.
+//
+// The returned logger also implements LevelEnabler.
+func WithErrorExpansion(logger Logger, opts ErrorExpansionOptions) LoggerFacade {
+	return WithProcessors(logger, ErrorExpansionProcessor(opts))
+}
+
+// ErrorExpansionProcessor returns a Processor that expands error valued fields.
+// See WithErrorExpansion for details.
+func ErrorExpansionProcessor(opts ErrorExpansionOptions) Processor {
+	if opts.TypeSuffix == "" {
+		opts.TypeSuffix = ".type"
+	}
+
+	if opts.ChainSuffix == "" {
+		opts.ChainSuffix = ".chain"
+	}
+
+	if opts.DetailsSeparator == "" {
+		opts.DetailsSeparator = "."
+	}
+
+	if opts.MaxChainDepth <= 0 {
+		opts.MaxChainDepth = 32
+	}
+
+	return func(_ context.Context, record *Record) bool {
+		var keys []string
+
+		for key, value := range record.Fields {
+			if _, ok := value.(error); ok {
+				keys = append(keys, key)
+			}
+		}
+
+		if len(keys) == 0 {
+			return true
+		}
+
+		// Original fields (used to decide if an added field would override one of the event)
+		fields := record.Fields
+
+		setField := func(key string, value interface{}) {
+			if _, ok := fields[key]; !ok {
+				record.SetField(key, value)
+			}
+		}
+
+		for _, key := range keys {
+			err := fields[key].(error)
+
+			record.SetField(key, err.Error())
+
+			if !opts.OmitType {
+				setField(key+opts.TypeSuffix, fmt.Sprintf("%T", err))
+			}
+
+			chain := errorChain(err, opts.MaxChainDepth)
+
+			if !opts.OmitChain && len(chain) > 1 {
+				messages := make([]string, 0, len(chain)-1)
+
+				for _, wrapped := range chain[1:] {
+					messages = append(messages, wrapped.Error())
+				}
+
+				setField(key+opts.ChainSuffix, messages)
+			}
+
+			if !opts.OmitDetails {
+				// Details of outer errors take precedence
+				for i := len(chain) - 1; i >= 0; i-- {
+					fielder, ok := chain[i].(interface{ Fields() map[string]interface{} })
+					if !ok {
+						continue
+					}
+
+					for detailKey, value := range fielder.Fields() {
+						setField(key+opts.DetailsSeparator+detailKey, value)
+					}
+				}
+			}
+		}
+
+		return true
+	}
+}
+
+// errorChain returns an error and the errors wrapped by it.
+func errorChain(err error, maxDepth int) []error {
+	chain := []error{err}
+
+	for len(chain) <= maxDepth {
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+
+		case interface{ Cause() error }:
+			err = e.Cause()
+
+		default:
+			err = nil
+		}
+
+		if err == nil {
+			break
+		}
+
+		chain = append(chain, err)
+	}
+
+	return chain
+}
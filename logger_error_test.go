@@ -0,0 +1,153 @@
+package logur_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+type wrappedError struct {
+	msg string
+	err error
+}
+
+func (e wrappedError) Error() string {
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e wrappedError) Unwrap() error {
+	return e.err
+}
+
+type causeError struct {
+	msg   string
+	cause error
+}
+
+func (e *causeError) Error() string {
+	return e.msg
+}
+
+func (e *causeError) Cause() error {
+	return e.cause
+}
+
+type detailedError struct {
+	error
+
+	fields map[string]interface{}
+}
+
+func (e detailedError) Unwrap() error {
+	return e.error
+}
+
+func (e detailedError) Fields() map[string]interface{} {
+	return e.fields
+}
+
+func TestWithErrorExpansion(t *testing.T) {
+	t.Run("Expand", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithErrorExpansion(testLogger, ErrorExpansionOptions{})
+
+		err := wrappedError{
+			msg: "request failed",
+			err: detailedError{
+				error: &causeError{
+					msg:   "database error",
+					cause: errors.New("connection refused"),
+				},
+				fields: map[string]interface{}{"code": 500, "retry": true},
+			},
+		}
+
+		fields := map[string]interface{}{
+			"error":       err,
+			"error.retry": false,
+			"key":         "value",
+		}
+
+		logger.Error("message", fields)
+
+		expectedFields := map[string]interface{}{
+			"error":       "request failed: database error",
+			"error.type":  "logur_test.wrappedError",
+			"error.chain": []string{"database error", "database error", "connection refused"},
+			"error.code":  500,
+			"error.retry": false,
+			"key":         "value",
+		}
+
+		if want, have := expectedFields, testLogger.LastEvent().Fields; !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected fields\nexpected: %#v\nactual:   %#v", want, have)
+		}
+
+		if _, ok := fields["error"].(error); !ok {
+			t.Error("original fields should not be modified")
+		}
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithErrorExpansion(testLogger, ErrorExpansionOptions{
+			TypeSuffix:       "_kind",
+			DetailsSeparator: "_",
+			OmitChain:        true,
+		})
+
+		err := detailedError{
+			error:  fmt.Errorf("error"),
+			fields: map[string]interface{}{"code": 400},
+		}
+
+		logger.Error("message", map[string]interface{}{"err": err})
+
+		expectedFields := map[string]interface{}{
+			"err":      "error",
+			"err_kind": "logur_test.detailedError",
+			"err_code": 400,
+		}
+
+		if want, have := expectedFields, testLogger.LastEvent().Fields; !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected fields\nexpected: %#v\nactual:   %#v", want, have)
+		}
+	})
+
+	t.Run("MaxChainDepth", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithErrorExpansion(testLogger, ErrorExpansionOptions{MaxChainDepth: 1, OmitType: true, OmitDetails: true})
+
+		err := wrappedError{msg: "a", err: wrappedError{msg: "b", err: errors.New("c")}}
+
+		logger.Error("message", map[string]interface{}{"error": err})
+
+		expectedFields := map[string]interface{}{
+			"error":       "a: b: c",
+			"error.chain": []string{"b: c"},
+		}
+
+		if want, have := expectedFields, testLogger.LastEvent().Fields; !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected fields\nexpected: %#v\nactual:   %#v", want, have)
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				testLogger := &TestLoggerFacade{}
+
+				return WithErrorExpansion(WithMinLevel(testLogger, level), ErrorExpansionOptions{}), testLogger
+			},
+		}
+
+		suite.Run(t)
+	})
+}
@@ -0,0 +1,622 @@
+package logur
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FluentMode is the mode of the Fluentd Forward protocol.
+type FluentMode int
+
+// Fluentd Forward protocol modes.
+const (
+	// FluentMessageMode sends every event in a separate message synchronously.
+	FluentMessageMode FluentMode = iota
+
+	// FluentPackedForwardMode buffers events and sends them in batches (one batch per tag).
+	FluentPackedForwardMode
+)
+
+// ErrFluentAckMismatch is returned when the ack received from the server does not match the chunk sent.
+var ErrFluentAckMismatch = errors.New("fluent: ack mismatch")
+
+// ErrFluentBufferFull is reported when an event is dropped because too many events are buffered in PackedForward mode.
+var ErrFluentBufferFull = errors.New("fluent: buffer is full, event dropped")
+
+// FluentLoggerConfig configures a FluentLogger.
+type FluentLoggerConfig struct {
+	// Network is either "tcp" (default) or "unix".
+	Network string
+
+	// Address is a host:port pair (or a socket path) of a Forward input (eg. Fluentd or Fluent Bit).
+	Address string
+
+	// Tag is the tag of events.
+	Tag string
+
+	// TagKey is the key of a field overriding the tag of an event.
+	// The field is removed from the record.
+	TagKey string
+
+	// Mode is the Forward protocol mode (defaults to FluentMessageMode).
+	Mode FluentMode
+
+	// RequireAck makes the logger wait for the server to acknowledge every chunk.
+	// Chunks that are not acknowledged are sent again.
+	RequireAck bool
+
+	// AckTimeout limits waiting for an ack (defaults to 5 seconds).
+	AckTimeout time.Duration
+
+	// BatchSize is the maximum number of events in a batch in PackedForward mode (defaults to 100).
+	BatchSize int
+
+	// FlushInterval is the maximum time events are buffered for in PackedForward mode (defaults to 1 second).
+	FlushInterval time.Duration
+
+	// MaxBufferedEvents is the maximum number of events waiting to be flushed in PackedForward mode
+	// (defaults to 100 times BatchSize).
+	// Events are dropped (and ErrFluentBufferFull is reported) when the server cannot keep up.
+	MaxBufferedEvents int
+
+	// MessageKey is the key of the message in records (defaults to "message").
+	MessageKey string
+
+	// LevelKey is the key of the level in records (defaults to "level").
+	LevelKey string
+
+	// MaxRetries is the maximum number of retries when sending a batch fails in PackedForward mode
+	// (defaults to 5, -1 disables retries).
+	MaxRetries int
+
+	// MinBackoff is the wait time before the first retry (defaults to 100 milliseconds).
+	// It is doubled for every subsequent retry.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum wait time between retries (defaults to 10 seconds).
+	MaxBackoff time.Duration
+
+	// DialTimeout limits establishing a connection (defaults to 5 seconds).
+	DialTimeout time.Duration
+
+	// WriteTimeout limits writing a message (defaults to 5 seconds).
+	WriteTimeout time.Duration
+
+	// Clock is used to timestamp events (defaults to the system clock).
+	Clock Clock
+
+	// ErrorHandler is called when events cannot be sent (after every retry failed).
+	// Errors are ignored by default.
+	ErrorHandler func(err error)
+}
+
+// FluentLogger sends log events to Fluentd (or Fluent Bit) using the Forward protocol.
+//
+// Records contain the message, the level and the fields of events.
+// Fields colliding with the message or the level key are prefixed with "fields."
+// (as many times as necessary to avoid colliding with other fields).
+//
+// Broken connections are established again.
+// While the server is unreachable, events fail fast (without dialing) until the reconnection backoff is over.
+// In message mode, events are sent synchronously: wrap the logger with an AsyncLogger to avoid blocking callers.
+// In PackedForward mode, failed batches are retried (with exponential backoff) by the background flusher.
+// Events logged after Close are dropped.
+//
+// The FluentLogger is safe for concurrent use.
+type FluentLogger struct {
+	config FluentLoggerConfig
+
+	sink *netSink
+
+	// batchMu guards closed, batches and buffered
+	batchMu sync.Mutex
+	closed  bool
+
+	// PackedForward mode
+	batches map[string]*fluentBatch
+
+	// buffered is the number of events in batches, including the ones being flushed
+	buffered    int
+	flushSignal chan struct{}
+	done        chan struct{}
+	flusherDone chan struct{}
+	closeOnce   sync.Once
+}
+
+type fluentBatch struct {
+	entries []byte
+	size    int
+}
+
+// NewFluentLogger returns a new FluentLogger connected to a Forward input.
+func NewFluentLogger(config FluentLoggerConfig) (*FluentLogger, error) {
+	if config.Network == "" {
+		config.Network = "tcp"
+	}
+
+	if config.AckTimeout == 0 {
+		config.AckTimeout = 5 * time.Second
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	if config.FlushInterval == 0 {
+		config.FlushInterval = time.Second
+	}
+
+	if config.MaxBufferedEvents <= 0 {
+		config.MaxBufferedEvents = 100 * config.BatchSize
+	}
+
+	if config.MessageKey == "" {
+		config.MessageKey = "message"
+	}
+
+	if config.LevelKey == "" {
+		config.LevelKey = "level"
+	}
+
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 5
+	}
+
+	if config.MinBackoff == 0 {
+		config.MinBackoff = 100 * time.Millisecond
+	}
+
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = 10 * time.Second
+	}
+
+	if config.DialTimeout == 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+
+	if config.WriteTimeout == 0 {
+		config.WriteTimeout = 5 * time.Second
+	}
+
+	if config.Clock == nil {
+		config.Clock = SystemClock()
+	}
+
+	l := &FluentLogger{
+		config: config,
+		sink: &netSink{
+			dial: func() (net.Conn, error) {
+				return net.DialTimeout(config.Network, config.Address, config.DialTimeout)
+			},
+			writeTimeout: config.WriteTimeout,
+		},
+	}
+
+	if err := l.sink.connect(); err != nil {
+		return nil, err
+	}
+
+	if config.Mode == FluentPackedForwardMode {
+		l.batches = make(map[string]*fluentBatch)
+		l.flushSignal = make(chan struct{}, 1)
+		l.done = make(chan struct{})
+		l.flusherDone = make(chan struct{})
+
+		go l.flusher()
+	}
+
+	return l, nil
+}
+
+// Flush sends buffered events (in PackedForward mode).
+func (l *FluentLogger) Flush() error {
+	if l.config.Mode != FluentPackedForwardMode {
+		return nil
+	}
+
+	l.batchMu.Lock()
+	batches := l.batches
+	l.batches = make(map[string]*fluentBatch)
+	l.batchMu.Unlock()
+
+	defer func() {
+		l.batchMu.Lock()
+		defer l.batchMu.Unlock()
+
+		for _, batch := range batches {
+			l.buffered -= batch.size
+		}
+	}()
+
+	var firstErr error
+
+	for _, tag := range sortedBatchTags(batches) {
+		batch := batches[tag]
+
+		var chunk string
+
+		if l.config.RequireAck {
+			var err error
+
+			chunk, err = newFluentChunkID()
+			if err != nil {
+				return err
+			}
+		}
+
+		msg := appendMsgpackArrayHeader(nil, 3)
+		msg = appendMsgpackString(msg, tag)
+		msg = appendMsgpackBinary(msg, batch.entries)
+		msg = l.appendOption(msg, chunk, batch.size)
+
+		if err := l.sendRetry(msg, chunk); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// Close sends buffered events and closes the connection.
+func (l *FluentLogger) Close() error {
+	var err error
+
+	l.closeOnce.Do(func() {
+		l.batchMu.Lock()
+		l.closed = true
+		l.batchMu.Unlock()
+
+		if l.config.Mode == FluentPackedForwardMode {
+			close(l.done)
+			<-l.flusherDone
+
+			err = l.Flush()
+		}
+
+		if cerr := l.sink.close(); err == nil {
+			err = cerr
+		}
+	})
+
+	return err
+}
+
+// Trace implements the Logger interface.
+func (l *FluentLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *FluentLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *FluentLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *FluentLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *FluentLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *FluentLogger) TraceContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *FluentLogger) DebugContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *FluentLogger) InfoContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *FluentLogger) WarnContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *FluentLogger) ErrorContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+func (l *FluentLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	var f map[string]interface{}
+	if len(fields) > 0 {
+		f = fields[0]
+	}
+
+	tag := l.config.Tag
+
+	if l.config.TagKey != "" {
+		if t, ok := f[l.config.TagKey].(string); ok && t != "" {
+			tag = t
+		}
+	}
+
+	now := l.config.Clock.Now()
+
+	if l.config.Mode == FluentPackedForwardMode {
+		l.batchMu.Lock()
+
+		if l.closed {
+			l.batchMu.Unlock()
+
+			return
+		}
+
+		if l.buffered >= l.config.MaxBufferedEvents {
+			l.batchMu.Unlock()
+
+			l.handleError(ErrFluentBufferFull)
+
+			return
+		}
+
+		batch, ok := l.batches[tag]
+		if !ok {
+			batch = &fluentBatch{}
+			l.batches[tag] = batch
+		}
+
+		batch.entries = appendMsgpackArrayHeader(batch.entries, 2)
+		batch.entries = appendMsgpackEventTime(batch.entries, now)
+		batch.entries = l.appendRecord(batch.entries, level, msg, f)
+		batch.size++
+		l.buffered++
+
+		full := batch.size >= l.config.BatchSize
+
+		l.batchMu.Unlock()
+
+		if full {
+			select {
+			case l.flushSignal <- struct{}{}:
+			default:
+			}
+		}
+
+		return
+	}
+
+	l.batchMu.Lock()
+	closed := l.closed
+	l.batchMu.Unlock()
+
+	if closed {
+		return
+	}
+
+	var chunk string
+
+	if l.config.RequireAck {
+		var err error
+
+		chunk, err = newFluentChunkID()
+		if err != nil {
+			l.handleError(err)
+
+			return
+		}
+	}
+
+	// The option is only sent when there is an option to send
+	var message []byte
+
+	if chunk != "" {
+		message = appendMsgpackArrayHeader(message, 4)
+	} else {
+		message = appendMsgpackArrayHeader(message, 3)
+	}
+
+	message = appendMsgpackString(message, tag)
+	message = appendMsgpackEventTime(message, now)
+	message = l.appendRecord(message, level, msg, f)
+
+	if chunk != "" {
+		message = l.appendOption(message, chunk, 0)
+	}
+
+	if err := l.send(message, chunk); err != nil {
+		l.handleError(err)
+	}
+}
+
+// appendRecord appends the record of an event.
+//
+// Fields colliding with the message or the level key are prefixed with "fields."
+// (repeatedly, as long as the prefixed key collides with another field).
+func (l *FluentLogger) appendRecord(b []byte, level Level, msg string, fields map[string]interface{}) []byte {
+	keys := make([]string, 0, len(fields))
+	names := make([]string, 0, len(fields))
+
+	for _, key := range sortedKeys(fields) {
+		if l.config.TagKey != "" && key == l.config.TagKey {
+			continue
+		}
+
+		name := key
+
+		if name == l.config.MessageKey || name == l.config.LevelKey {
+			name = "fields." + name
+
+			for {
+				if _, ok := fields[name]; !ok {
+					break
+				}
+
+				name = "fields." + name
+			}
+		}
+
+		keys = append(keys, key)
+		names = append(names, name)
+	}
+
+	b = appendMsgpackMapHeader(b, len(keys)+2)
+	b = appendMsgpackString(b, l.config.MessageKey)
+	b = appendMsgpackString(b, msg)
+	b = appendMsgpackString(b, l.config.LevelKey)
+	b = appendMsgpackString(b, level.String())
+
+	for i, key := range keys {
+		b = appendMsgpackString(b, names[i])
+		b = appendMsgpackValue(b, fields[key])
+	}
+
+	return b
+}
+
+// appendOption appends the option map of a message.
+func (l *FluentLogger) appendOption(b []byte, chunk string, size int) []byte {
+	n := 0
+
+	if chunk != "" {
+		n++
+	}
+
+	if size > 0 {
+		n++
+	}
+
+	b = appendMsgpackMapHeader(b, n)
+
+	if size > 0 {
+		b = appendMsgpackString(b, "size")
+		b = appendMsgpackInt(b, int64(size))
+	}
+
+	if chunk != "" {
+		b = appendMsgpackString(b, "chunk")
+		b = appendMsgpackString(b, chunk)
+	}
+
+	return b
+}
+
+// flusher flushes batches periodically (or when a batch is full) in PackedForward mode.
+func (l *FluentLogger) flusher() {
+	defer close(l.flusherDone)
+
+	ticker := time.NewTicker(l.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.flushSignal:
+		case <-l.done:
+			return
+		}
+
+		if err := l.Flush(); err != nil {
+			l.handleError(err)
+		}
+	}
+}
+
+// sendRetry sends a message, retrying with exponential backoff.
+func (l *FluentLogger) sendRetry(message []byte, chunk string) error {
+	backoff := l.config.MinBackoff
+
+	for retry := 0; ; retry++ {
+		err := l.send(message, chunk)
+		if err == nil {
+			return nil
+		}
+
+		if l.config.MaxRetries < 0 || retry >= l.config.MaxRetries {
+			return err
+		}
+
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > l.config.MaxBackoff {
+			backoff = l.config.MaxBackoff
+		}
+	}
+}
+
+// send sends a message (and waits for the ack of the chunk if required).
+func (l *FluentLogger) send(message []byte, chunk string) error {
+	if chunk == "" {
+		return l.sink.write(message)
+	}
+
+	return l.sink.exchange(message, func(conn net.Conn) error {
+		_ = conn.SetReadDeadline(time.Now().Add(l.config.AckTimeout))
+
+		response, err := readMsgpackStringMap(&connByteReader{conn: conn})
+		if err != nil {
+			return err
+		}
+
+		if response["ack"] != chunk {
+			return ErrFluentAckMismatch
+		}
+
+		return nil
+	})
+}
+
+// connByteReader reads a connection byte by byte, so that nothing is read past the ack.
+type connByteReader struct {
+	conn net.Conn
+	buf  [1]byte
+}
+
+func (r *connByteReader) ReadByte() (byte, error) {
+	if _, err := io.ReadFull(r.conn, r.buf[:]); err != nil {
+		return 0, err
+	}
+
+	return r.buf[0], nil
+}
+
+func (l *FluentLogger) handleError(err error) {
+	if l.config.ErrorHandler != nil {
+		l.config.ErrorHandler(err)
+	}
+}
+
+// newFluentChunkID returns a random chunk ID.
+func newFluentChunkID() (string, error) {
+	var id [16]byte
+
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(id[:]), nil
+}
+
+func sortedBatchTags(batches map[string]*fluentBatch) []string {
+	tags := make([]string, 0, len(batches))
+
+	for tag := range batches {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
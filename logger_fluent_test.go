@@ -0,0 +1,678 @@
+package logur_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+)
+
+// decodeMsgpack decodes a single MessagePack value (only the types used by the Forward protocol).
+func decodeMsgpack(r *bufio.Reader) (interface{}, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	readN := func(n int) ([]byte, error) {
+		b := make([]byte, n)
+		_, err := io.ReadFull(r, b)
+
+		return b, err
+	}
+
+	readUint := func(n int) (uint64, error) {
+		b, err := readN(n)
+		if err != nil {
+			return 0, err
+		}
+
+		var v uint64
+
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+
+		return v, nil
+	}
+
+	decodeArray := func(n int) (interface{}, error) {
+		a := make([]interface{}, n)
+
+		for i := range a {
+			if a[i], err = decodeMsgpack(r); err != nil {
+				return nil, err
+			}
+		}
+
+		return a, nil
+	}
+
+	decodeMap := func(n int) (interface{}, error) {
+		m := make(map[string]interface{}, n)
+
+		for i := 0; i < n; i++ {
+			key, err := decodeMsgpack(r)
+			if err != nil {
+				return nil, err
+			}
+
+			if m[key.(string)], err = decodeMsgpack(r); err != nil {
+				return nil, err
+			}
+		}
+
+		return m, nil
+	}
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+
+	case c&0xf0 == 0x80:
+		return decodeMap(int(c & 0x0f))
+
+	case c&0xf0 == 0x90:
+		return decodeArray(int(c & 0x0f))
+
+	case c&0xe0 == 0xa0:
+		b, err := readN(int(c & 0x1f))
+
+		return string(b), err
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+
+	case 0xc2:
+		return false, nil
+
+	case 0xc3:
+		return true, nil
+
+	case 0xc4, 0xc5, 0xc6, 0xd9, 0xda, 0xdb:
+		sizes := map[byte]int{0xc4: 1, 0xc5: 2, 0xc6: 4, 0xd9: 1, 0xda: 2, 0xdb: 4}
+
+		n, err := readUint(sizes[c])
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := readN(int(n))
+
+		if c >= 0xd9 {
+			return string(b), err
+		}
+
+		return b, err
+
+	case 0xca:
+		v, err := readUint(4)
+
+		return math.Float32frombits(uint32(v)), err
+
+	case 0xcb:
+		v, err := readUint(8)
+
+		return math.Float64frombits(v), err
+
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := readUint(1 << (c - 0xcc))
+
+		return int64(v), err
+
+	case 0xd0:
+		v, err := readUint(1)
+
+		return int64(int8(v)), err
+
+	case 0xd1:
+		v, err := readUint(2)
+
+		return int64(int16(v)), err
+
+	case 0xd2:
+		v, err := readUint(4)
+
+		return int64(int32(v)), err
+
+	case 0xd3:
+		v, err := readUint(8)
+
+		return int64(v), err
+
+	case 0xdc, 0xdd:
+		n, err := readUint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeArray(int(n))
+
+	case 0xde, 0xdf:
+		n, err := readUint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeMap(int(n))
+
+	case 0xd7:
+		b, err := readN(9)
+		if err != nil {
+			return nil, err
+		}
+
+		return time.Unix(int64(binary.BigEndian.Uint32(b[1:5])), int64(binary.BigEndian.Uint32(b[5:9]))).UTC(), nil
+	}
+
+	return nil, errors.New("unsupported type")
+}
+
+// fluentServer is a Forward protocol stand-in receiving messages.
+type fluentServer struct {
+	listener net.Listener
+	messages chan []interface{}
+
+	// dropAcks is the number of messages to drop the connection after (without acking them)
+	dropAcks int32
+}
+
+func newFluentServer(t *testing.T) *fluentServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &fluentServer{
+		listener: listener,
+		messages: make(chan []interface{}, 100),
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *fluentServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+
+	for {
+		message, err := decodeMsgpack(r)
+		if err != nil {
+			return
+		}
+
+		s.messages <- message.([]interface{})
+
+		option, ok := message.([]interface{})[len(message.([]interface{}))-1].(map[string]interface{})
+		if !ok || option["chunk"] == nil {
+			continue
+		}
+
+		if atomic.AddInt32(&s.dropAcks, -1) >= 0 {
+			return
+		}
+
+		chunk := option["chunk"].(string)
+
+		ack := []byte{0x81, 0xa3, 'a', 'c', 'k', 0xa0 | byte(len(chunk))}
+		ack = append(ack, chunk...)
+
+		if _, err := conn.Write(ack); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fluentServer) receive(t *testing.T) []interface{} {
+	t.Helper()
+
+	select {
+	case message := <-s.messages:
+		return message
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+
+		return nil
+	}
+}
+
+func (s *fluentServer) Close() {
+	_ = s.listener.Close()
+}
+
+// nolint: gochecknoglobals
+var fluentTestTime = time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+
+func newFluentTestConfig(address string) FluentLoggerConfig {
+	return FluentLoggerConfig{
+		Address:    address,
+		Tag:        "app",
+		TagKey:     "tag",
+		MinBackoff: time.Millisecond,
+		Clock:      ClockFunc(func() time.Time { return fluentTestTime }),
+	}
+}
+
+func TestFluentLogger(t *testing.T) {
+	t.Run("MessageMode", func(t *testing.T) {
+		server := newFluentServer(t)
+		defer server.Close()
+
+		config := newFluentTestConfig(server.listener.Addr().String())
+		config.RequireAck = true
+
+		var errs []error
+
+		config.ErrorHandler = func(err error) { errs = append(errs, err) }
+
+		logger, err := NewFluentLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Warn("message", map[string]interface{}{
+			"key":     "value",
+			"number":  -1234,
+			"float":   1.5,
+			"bool":    true,
+			"nil":     nil,
+			"message": "collision",
+			"nested":  map[string]interface{}{"list": []interface{}{1, "two"}},
+		})
+		logger.Info("message", map[string]interface{}{"tag": "other"})
+
+		message := server.receive(t)
+
+		if want, have := 4, len(message); want != have {
+			t.Fatalf("unexpected message length\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if want, have := "app", message[0]; want != have {
+			t.Errorf("unexpected tag\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := fluentTestTime, message[1]; want != have {
+			t.Errorf("unexpected time\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		expectedRecord := map[string]interface{}{
+			"message":        "message",
+			"level":          "warn",
+			"key":            "value",
+			"number":         int64(-1234),
+			"float":          1.5,
+			"bool":           true,
+			"nil":            nil,
+			"fields.message": "collision",
+			"nested":         map[string]interface{}{"list": []interface{}{int64(1), "two"}},
+		}
+
+		if want, have := expectedRecord, message[2]; !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected record\nexpected: %#v\nactual:   %#v", want, have)
+		}
+
+		message = server.receive(t)
+
+		if want, have := "other", message[0]; want != have {
+			t.Errorf("unexpected tag\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := map[string]interface{}{"message": "message", "level": "info"}, message[2]; !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected record\nexpected: %#v\nactual:   %#v", want, have)
+		}
+
+		if len(errs) > 0 {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+
+	t.Run("RecordKeys", func(t *testing.T) {
+		server := newFluentServer(t)
+		defer server.Close()
+
+		config := newFluentTestConfig(server.listener.Addr().String())
+		config.TagKey = ""
+
+		logger, err := NewFluentLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info("message", map[string]interface{}{
+			"":               "empty",
+			"a":              "b",
+			"message":        "collision",
+			"fields.message": "prefixed",
+		})
+		logger.Info("next")
+
+		expectedRecord := map[string]interface{}{
+			"message":               "message",
+			"level":                 "info",
+			"":                      "empty",
+			"a":                     "b",
+			"fields.message":        "prefixed",
+			"fields.fields.message": "collision",
+		}
+
+		if want, have := expectedRecord, server.receive(t)[2]; !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected record\nexpected: %#v\nactual:   %#v", want, have)
+		}
+
+		// The stream stays in sync
+		if want, have := "next", server.receive(t)[2].(map[string]interface{})["message"]; want != have {
+			t.Errorf("unexpected message\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("NoAck", func(t *testing.T) {
+		server := newFluentServer(t)
+		defer server.Close()
+
+		logger, err := NewFluentLogger(newFluentTestConfig(server.listener.Addr().String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info("message")
+
+		if want, have := 3, len(server.receive(t)); want != have {
+			t.Errorf("unexpected message length\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("PackedForwardMode", func(t *testing.T) {
+		server := newFluentServer(t)
+		defer server.Close()
+
+		config := newFluentTestConfig(server.listener.Addr().String())
+		config.Mode = FluentPackedForwardMode
+		config.RequireAck = true
+		config.FlushInterval = time.Hour
+
+		logger, err := NewFluentLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info("message 1")
+		logger.Info("message 2", map[string]interface{}{"tag": "other"})
+		logger.Info("message 3")
+
+		if err := logger.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, expected := range []struct {
+			tag      string
+			messages []string
+		}{
+			{tag: "app", messages: []string{"message 1", "message 3"}},
+			{tag: "other", messages: []string{"message 2"}},
+		} {
+			message := server.receive(t)
+
+			if want, have := expected.tag, message[0]; want != have {
+				t.Errorf("unexpected tag\nexpected: %v\nactual:   %v", want, have)
+			}
+
+			option := message[2].(map[string]interface{})
+
+			if want, have := int64(len(expected.messages)), option["size"]; want != have {
+				t.Errorf("unexpected size\nexpected: %v\nactual:   %v", want, have)
+			}
+
+			r := bufio.NewReader(bytes.NewReader(message[1].([]byte)))
+
+			for _, msg := range expected.messages {
+				entry, err := decodeMsgpack(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if want, have := fluentTestTime, entry.([]interface{})[0]; want != have {
+					t.Errorf("unexpected time\nexpected: %v\nactual:   %v", want, have)
+				}
+
+				if want, have := msg, entry.([]interface{})[1].(map[string]interface{})["message"]; want != have {
+					t.Errorf("unexpected message\nexpected: %v\nactual:   %v", want, have)
+				}
+			}
+
+			if _, err := r.ReadByte(); err != io.EOF {
+				t.Error("unexpected entries in the batch")
+			}
+		}
+	})
+
+	t.Run("BatchSize", func(t *testing.T) {
+		server := newFluentServer(t)
+		defer server.Close()
+
+		config := newFluentTestConfig(server.listener.Addr().String())
+		config.Mode = FluentPackedForwardMode
+		config.BatchSize = 2
+		config.FlushInterval = time.Hour
+
+		logger, err := NewFluentLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info("message 1")
+		logger.Info("message 2")
+
+		message := server.receive(t)
+
+		if want, have := int64(2), message[2].(map[string]interface{})["size"]; want != have {
+			t.Errorf("unexpected size\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("BufferFull", func(t *testing.T) {
+		server := newFluentServer(t)
+		defer server.Close()
+
+		config := newFluentTestConfig(server.listener.Addr().String())
+		config.Mode = FluentPackedForwardMode
+		config.FlushInterval = time.Hour
+		config.MaxBufferedEvents = 2
+
+		var errs []error
+
+		config.ErrorHandler = func(err error) { errs = append(errs, err) }
+
+		logger, err := NewFluentLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info("message 1")
+		logger.Info("message 2")
+		logger.Info("message 3")
+
+		if len(errs) != 1 || errs[0] != ErrFluentBufferFull {
+			t.Errorf("unexpected errors\nexpected: %v\nactual:   %v", ErrFluentBufferFull, errs)
+		}
+
+		if err := logger.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, have := int64(2), server.receive(t)[2].(map[string]interface{})["size"]; want != have {
+			t.Errorf("unexpected size\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		logger.Info("message 4")
+
+		if len(errs) != 1 {
+			t.Errorf("events should be buffered again after a flush: %v", errs)
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		for _, mode := range []FluentMode{FluentMessageMode, FluentPackedForwardMode} {
+			server := newFluentServer(t)
+
+			config := newFluentTestConfig(server.listener.Addr().String())
+			config.Mode = mode
+
+			logger, err := NewFluentLogger(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := logger.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			logger.Info("message")
+
+			if err := logger.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case message := <-server.messages:
+				t.Errorf("events logged after close should be dropped, got: %v", message)
+
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			server.Close()
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		server := newFluentServer(t)
+		defer server.Close()
+
+		server.dropAcks = 1
+
+		config := newFluentTestConfig(server.listener.Addr().String())
+		config.RequireAck = true
+
+		var errs []error
+
+		config.ErrorHandler = func(err error) { errs = append(errs, err) }
+
+		logger, err := NewFluentLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info("message")
+
+		first, second := server.receive(t), server.receive(t)
+
+		if want, have := first[3], second[3]; !reflect.DeepEqual(want, have) {
+			t.Errorf("the same chunk should be sent again\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if len(errs) > 0 {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+
+	t.Run("RetryFailure", func(t *testing.T) {
+		server := newFluentServer(t)
+		defer server.Close()
+
+		server.dropAcks = 100
+
+		config := newFluentTestConfig(server.listener.Addr().String())
+		config.Mode = FluentPackedForwardMode
+		config.RequireAck = true
+		config.MaxRetries = 2
+
+		logger, err := NewFluentLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info("message")
+
+		if err := logger.Flush(); err == nil {
+			t.Fatal("flush is expected to fail")
+		}
+
+		// Every retry sends the batch at least once
+		for i := 0; i < 3; i++ {
+			server.receive(t)
+		}
+	})
+
+	t.Run("ServerDown", func(t *testing.T) {
+		server := newFluentServer(t)
+
+		// The server drops the connection after the first message and stops accepting new ones
+		server.dropAcks = 100
+
+		config := newFluentTestConfig(server.listener.Addr().String())
+		config.RequireAck = true
+		config.MinBackoff = time.Minute
+
+		var errs []error
+
+		config.ErrorHandler = func(err error) { errs = append(errs, err) }
+
+		logger, err := NewFluentLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		server.Close()
+
+		start := time.Now()
+
+		for i := 0; i < 3; i++ {
+			logger.Info("message")
+		}
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("logging should fail fast while the server is down, took %s", elapsed)
+		}
+
+		if len(errs) == 0 {
+			t.Error("errors are expected while the server is down")
+		}
+	})
+}
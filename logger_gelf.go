@@ -0,0 +1,328 @@
+package logur
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GELFCompression is the compression of GELF messages sent over UDP.
+type GELFCompression int
+
+// GELF compression types.
+const (
+	// GELFCompressGzip compresses messages using gzip.
+	GELFCompressGzip GELFCompression = iota
+
+	// GELFCompressZlib compresses messages using zlib.
+	GELFCompressZlib
+
+	// GELFCompressNone disables compression.
+	GELFCompressNone
+)
+
+// GELF chunking protocol constants.
+const (
+	gelfChunkHeaderSize = 12
+	gelfMaxChunks       = 128
+)
+
+// ErrGELFMessageTooLarge is returned when a message does not fit in the maximum number of chunks.
+var ErrGELFMessageTooLarge = errors.New("gelf: message too large")
+
+// GELFLoggerConfig configures a GELFLogger.
+type GELFLoggerConfig struct {
+	// Network is either "udp" (default) or "tcp".
+	Network string
+
+	// Address is a host:port pair of a GELF input.
+	Address string
+
+	// Host identifies the host sending the messages (defaults to os.Hostname).
+	Host string
+
+	// Compression of messages sent over UDP (defaults to gzip).
+	// Messages sent over TCP are never compressed.
+	Compression GELFCompression
+
+	// ChunkSize is the maximum size of UDP datagrams (defaults to 1420).
+	// Larger messages are split using the GELF chunking protocol.
+	ChunkSize int
+
+	// DialTimeout limits establishing a connection (defaults to 5 seconds).
+	DialTimeout time.Duration
+
+	// WriteTimeout limits writing a message (defaults to 5 seconds).
+	WriteTimeout time.Duration
+
+	// Clock is used to timestamp messages (defaults to the system clock).
+	Clock Clock
+
+	// ErrorHandler is called when a message cannot be sent.
+	// Errors are ignored by default.
+	ErrorHandler func(err error)
+}
+
+// GELFLogger sends log events to Graylog (or any other GELF 1.1 input).
+//
+// The message is sent as short_message, levels are mapped to syslog levels
+// (Error to 3, Warn to 4, Info to 6, Debug and Trace to 7)
+// and fields are sent as additional fields (prefixed with _).
+// Numbers are sent as they are, other values are converted to strings.
+//
+// Messages are null byte delimited over TCP.
+//
+// The GELFLogger is safe for concurrent use.
+type GELFLogger struct {
+	config GELFLoggerConfig
+
+	sink *netSink
+}
+
+// NewGELFLogger returns a new GELFLogger connected to a GELF input.
+func NewGELFLogger(config GELFLoggerConfig) (*GELFLogger, error) {
+	if config.Network == "" {
+		config.Network = "udp"
+	}
+
+	if config.Host == "" {
+		config.Host, _ = os.Hostname()
+	}
+
+	if config.ChunkSize <= gelfChunkHeaderSize {
+		config.ChunkSize = 1420
+	}
+
+	if config.DialTimeout == 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+
+	if config.WriteTimeout == 0 {
+		config.WriteTimeout = 5 * time.Second
+	}
+
+	if config.Clock == nil {
+		config.Clock = SystemClock()
+	}
+
+	l := &GELFLogger{
+		config: config,
+		sink: &netSink{
+			dial: func() (net.Conn, error) {
+				return net.DialTimeout(config.Network, config.Address, config.DialTimeout)
+			},
+			writeTimeout: config.WriteTimeout,
+		},
+	}
+
+	if err := l.sink.connect(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Close closes the connection.
+func (l *GELFLogger) Close() error {
+	return l.sink.close()
+}
+
+// Trace implements the Logger interface.
+func (l *GELFLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *GELFLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *GELFLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *GELFLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *GELFLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *GELFLogger) TraceContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *GELFLogger) DebugContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *GELFLogger) InfoContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *GELFLogger) WarnContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *GELFLogger) ErrorContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+func (l *GELFLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	buf := outputBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	defer outputBufferPool.Put(buf)
+
+	var f map[string]interface{}
+	if len(fields) > 0 {
+		f = fields[0]
+	}
+
+	l.encode(buf, level, msg, f)
+
+	var err error
+
+	if strings.HasPrefix(l.config.Network, "udp") {
+		err = l.sendUDP(buf.Bytes())
+	} else {
+		buf.WriteByte(0)
+
+		err = l.sink.write(buf.Bytes())
+	}
+
+	if err != nil && l.config.ErrorHandler != nil {
+		l.config.ErrorHandler(err)
+	}
+}
+
+// encode writes a GELF 1.1 message.
+func (l *GELFLogger) encode(buf *bytes.Buffer, level Level, msg string, fields map[string]interface{}) {
+	now := l.config.Clock.Now()
+
+	buf.WriteString(`{"version":"1.1","host":`)
+	writeJSONString(buf, l.config.Host)
+	buf.WriteString(`,"short_message":`)
+	writeJSONString(buf, msg)
+	buf.WriteString(`,"timestamp":`)
+	buf.WriteString(strconv.FormatFloat(float64(now.UnixNano()/int64(time.Millisecond))/1000, 'f', 3, 64))
+	buf.WriteString(`,"level":`)
+	buf.WriteString(strconv.Itoa(syslogSeverity(level)))
+
+	for _, key := range sortedKeys(fields) {
+		buf.WriteByte(',')
+		writeJSONString(buf, gelfFieldName(key))
+		buf.WriteByte(':')
+
+		switch v := fields[key].(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			writeJSONValue(buf, v)
+
+		default:
+			writeJSONString(buf, logfmtValue(v))
+		}
+	}
+
+	buf.WriteByte('}')
+}
+
+// gelfFieldName converts a key to a valid additional field name:
+// prefixed with _ and containing only letters, digits, underscores, dashes and dots.
+func gelfFieldName(key string) string {
+	name := make([]byte, 0, len(key)+1)
+	name = append(name, '_')
+
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-', c == '.':
+			name = append(name, c)
+
+		default:
+			name = append(name, '_')
+		}
+	}
+
+	// _id is reserved
+	if string(name) == "_id" {
+		return "__id"
+	}
+
+	return string(name)
+}
+
+// sendUDP compresses a message and sends it in one or more datagrams.
+func (l *GELFLogger) sendUDP(message []byte) error {
+	var compressed bytes.Buffer
+
+	switch l.config.Compression {
+	case GELFCompressGzip:
+		w := gzip.NewWriter(&compressed)
+		_, _ = w.Write(message)
+		_ = w.Close()
+
+		message = compressed.Bytes()
+
+	case GELFCompressZlib:
+		w := zlib.NewWriter(&compressed)
+		_, _ = w.Write(message)
+		_ = w.Close()
+
+		message = compressed.Bytes()
+	}
+
+	if len(message) <= l.config.ChunkSize {
+		return l.sink.write(message)
+	}
+
+	dataSize := l.config.ChunkSize - gelfChunkHeaderSize
+	count := (len(message) + dataSize - 1) / dataSize
+
+	if count > gelfMaxChunks {
+		return ErrGELFMessageTooLarge
+	}
+
+	var id [8]byte
+
+	if _, err := rand.Read(id[:]); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, 0, l.config.ChunkSize)
+
+	for i := 0; i < count; i++ {
+		end := (i + 1) * dataSize
+		if end > len(message) {
+			end = len(message)
+		}
+
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, message[i*dataSize:end]...)
+
+		if err := l.sink.write(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
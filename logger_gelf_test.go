@@ -0,0 +1,293 @@
+package logur_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+)
+
+// readGELFMessage reads a (possibly chunked and compressed) GELF message from a UDP connection.
+func readGELFMessage(t *testing.T, conn net.PacketConn) (map[string]interface{}, int) {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var (
+		message   []byte
+		datagrams int
+		chunks    [][]byte
+	)
+
+	for {
+		buf := make([]byte, 65536)
+
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		datagrams++
+		datagram := buf[:n]
+
+		if !bytes.HasPrefix(datagram, []byte{0x1e, 0x0f}) {
+			message = datagram
+
+			break
+		}
+
+		seq, count := int(datagram[10]), int(datagram[11])
+
+		if chunks == nil {
+			chunks = make([][]byte, count)
+		}
+
+		chunks[seq] = datagram[12:]
+
+		if datagrams == count {
+			message = bytes.Join(chunks, nil)
+
+			break
+		}
+	}
+
+	var r io.Reader = bytes.NewReader(message)
+
+	switch {
+	case bytes.HasPrefix(message, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r = gz
+
+	case message[0] == 0x78:
+		z, err := zlib.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r = z
+	}
+
+	payload, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m map[string]interface{}
+
+	if err := json.Unmarshal(payload, &m); err != nil {
+		t.Fatalf("invalid message %q: %v", payload, err)
+	}
+
+	return m, datagrams
+}
+
+func newGELFTestConfig(network string, address string) GELFLoggerConfig {
+	return GELFLoggerConfig{
+		Network: network,
+		Address: address,
+		Host:    "host",
+		Clock:   ClockFunc(func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 678000000, time.UTC) }),
+	}
+}
+
+func TestGELFLogger(t *testing.T) {
+	fields := map[string]interface{}{
+		"key":      "value",
+		"number":   12,
+		"float":    1.5,
+		"bool":     true,
+		"id":       "reserved",
+		"key with": "space",
+	}
+
+	expected := map[string]interface{}{
+		"version":       "1.1",
+		"host":          "host",
+		"short_message": "message",
+		"timestamp":     1577934245.678,
+		"level":         float64(3),
+		"_key":          "value",
+		"_number":       float64(12),
+		"_float":        1.5,
+		"_bool":         "true",
+		"__id":          "reserved",
+		"_key_with":     "space",
+	}
+
+	assertMessage := func(t *testing.T, message map[string]interface{}) {
+		t.Helper()
+
+		if want, have := len(expected), len(message); want != have {
+			t.Errorf("unexpected number of fields\nexpected: %d\nactual:   %d (%v)", want, have, message)
+		}
+
+		for key, value := range expected {
+			if want, have := value, message[key]; want != have {
+				t.Errorf("unexpected value of %s\nexpected: %v\nactual:   %v", key, want, have)
+			}
+		}
+	}
+
+	compressions := map[string]GELFCompression{
+		"Gzip": GELFCompressGzip,
+		"Zlib": GELFCompressZlib,
+		"None": GELFCompressNone,
+	}
+
+	for name, compression := range compressions {
+		compression := compression
+
+		t.Run("UDP"+name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			config := newGELFTestConfig("udp", conn.LocalAddr().String())
+			config.Compression = compression
+
+			logger, err := NewGELFLogger(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer logger.Close()
+
+			logger.Error("message", fields)
+
+			message, datagrams := readGELFMessage(t, conn)
+
+			assertMessage(t, message)
+
+			if want, have := 1, datagrams; want != have {
+				t.Errorf("unexpected number of datagrams\nexpected: %d\nactual:   %d", want, have)
+			}
+		})
+	}
+
+	t.Run("Chunking", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		config := newGELFTestConfig("udp", conn.LocalAddr().String())
+		config.Compression = GELFCompressNone
+		config.ChunkSize = 100
+
+		logger, err := NewGELFLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		msg := strings.Repeat("a", 1000)
+
+		logger.Info(msg)
+
+		message, datagrams := readGELFMessage(t, conn)
+
+		if want, have := msg, message["short_message"]; want != have {
+			t.Errorf("unexpected message\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if datagrams < 10 {
+			t.Errorf("message should be chunked, got %d datagrams", datagrams)
+		}
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		var errs []error
+
+		config := newGELFTestConfig("udp", conn.LocalAddr().String())
+		config.Compression = GELFCompressNone
+		config.ChunkSize = 20
+		config.ErrorHandler = func(err error) { errs = append(errs, err) }
+
+		logger, err := NewGELFLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info(strings.Repeat("a", 2000))
+
+		if len(errs) != 1 || errs[0] != ErrGELFMessageTooLarge {
+			t.Errorf("unexpected errors\nexpected: %v\nactual:   %v", ErrGELFMessageTooLarge, errs)
+		}
+	})
+
+	t.Run("TCP", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+
+		messages := make(chan []byte, 10)
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			r := bufio.NewReader(conn)
+
+			for {
+				message, err := r.ReadBytes(0)
+				if err != nil {
+					return
+				}
+
+				messages <- message
+			}
+		}()
+
+		logger, err := NewGELFLogger(newGELFTestConfig("tcp", listener.Addr().String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Error("message", fields)
+		logger.Error("message", fields)
+
+		for i := 0; i < 2; i++ {
+			select {
+			case payload := <-messages:
+				var message map[string]interface{}
+
+				if err := json.Unmarshal(bytes.TrimSuffix(payload, []byte{0}), &message); err != nil {
+					t.Fatalf("invalid message %q: %v", payload, err)
+				}
+
+				assertMessage(t, message)
+
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for message")
+			}
+		}
+	})
+}
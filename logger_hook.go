@@ -0,0 +1,223 @@
+package logur
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// HookEvent is a log event passed to hooks.
+type HookEvent struct {
+	// Context is the context passed to *Context methods or context.Background() for the rest.
+	Context context.Context
+
+	Level   Level
+	Message string
+
+	// Fields of the event. Hooks must not modify them.
+	Fields map[string]interface{}
+}
+
+// Hook is called for log events of certain levels (eg. to increment a metric or to send a notification).
+type Hook interface {
+	// Levels returns the levels the hook should be fired for.
+	Levels() []Level
+
+	// Fire is called for every log event of the hook levels.
+	Fire(event HookEvent) error
+}
+
+// NewHookFunc returns a Hook calling a function for log events of the given levels.
+func NewHookFunc(fn func(event HookEvent) error, levels ...Level) Hook {
+	return hookFunc{
+		fn:     fn,
+		levels: levels,
+	}
+}
+
+type hookFunc struct {
+	fn     func(event HookEvent) error
+	levels []Level
+}
+
+func (h hookFunc) Levels() []Level {
+	return h.levels
+}
+
+func (h hookFunc) Fire(event HookEvent) error {
+	return h.fn(event)
+}
+
+// HookLoggerConfig configures a HookLogger.
+type HookLoggerConfig struct {
+	// ErrorHandler is called when a hook returns an error or panics.
+	// Errors are ignored by default.
+	ErrorHandler func(hook Hook, err error)
+}
+
+// HookLogger fires hooks registered for the level of log events before passing them to a logger.
+//
+// Hooks are called synchronously, in the order they were added, and only for enabled levels
+// (if the underlying logger implements LevelEnabler).
+// A hook returning an error or panicking never prevents the event from being logged or other hooks from being fired.
+type HookLogger struct {
+	logger       LoggerFacade
+	levelEnabler LevelEnabler
+
+	errorHandler func(hook Hook, err error)
+
+	hooks map[Level][]Hook
+	mu    sync.RWMutex
+}
+
+// NewHookLogger returns a new HookLogger.
+func NewHookLogger(logger Logger, config HookLoggerConfig, hooks ...Hook) *HookLogger {
+	l := &HookLogger{
+		logger:       ensureLoggerFacade(logger),
+		errorHandler: config.ErrorHandler,
+		hooks:        make(map[Level][]Hook),
+	}
+
+	if levelEnabler, ok := logger.(LevelEnabler); ok {
+		l.levelEnabler = levelEnabler
+	}
+
+	for _, hook := range hooks {
+		l.AddHook(hook)
+	}
+
+	return l
+}
+
+// AddHook registers a hook for its levels.
+func (l *HookLogger) AddHook(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, level := range hook.Levels() {
+		// Copy on write, so that hooks can be fired without holding the lock
+		hooks := make([]Hook, 0, len(l.hooks[level])+1)
+		hooks = append(hooks, l.hooks[level]...)
+		hooks = append(hooks, hook)
+
+		l.hooks[level] = hooks
+	}
+}
+
+// Trace implements the Logger interface.
+func (l *HookLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *HookLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *HookLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *HookLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *HookLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(nil, Error, msg, fields)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *HookLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *HookLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *HookLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *HookLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *HookLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, Error, msg, fields)
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *HookLogger) LevelEnabled(level Level) bool {
+	if l.levelEnabler != nil {
+		return l.levelEnabler.LevelEnabled(level)
+	}
+
+	return true
+}
+
+// log fires the hooks of the level and passes the event to the underlying logger.
+// A nil context means the event was logged without a context.
+func (l *HookLogger) log(ctx context.Context, level Level, msg string, fields []map[string]interface{}) {
+	if !l.LevelEnabled(level) {
+		return
+	}
+
+	l.mu.RLock()
+	hooks := l.hooks[level]
+	l.mu.RUnlock()
+
+	if len(hooks) > 0 {
+		event := HookEvent{
+			Context: ctx,
+			Level:   level,
+			Message: msg,
+		}
+
+		if event.Context == nil {
+			event.Context = context.Background()
+		}
+
+		if len(fields) > 0 {
+			event.Fields = fields[0]
+		}
+
+		for _, hook := range hooks {
+			l.fire(hook, event)
+		}
+	}
+
+	if ctx != nil {
+		LevelContextFunc(l.logger, level)(ctx, msg, fields...)
+
+		return
+	}
+
+	LevelFunc(l.logger, level)(msg, fields...)
+}
+
+// fire calls a hook recovering from panics.
+func (l *HookLogger) fire(hook Hook, event HookEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.handleError(hook, fmt.Errorf("hook panicked: %v", r))
+		}
+	}()
+
+	if err := hook.Fire(event); err != nil {
+		l.handleError(hook, err)
+	}
+}
+
+func (l *HookLogger) handleError(hook Hook, err error) {
+	if l.errorHandler != nil {
+		l.errorHandler(hook, err)
+	}
+}
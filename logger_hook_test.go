@@ -0,0 +1,161 @@
+package logur_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+func TestHookLogger(t *testing.T) {
+	t.Run("Levels", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		var events []HookEvent
+
+		hook := NewHookFunc(func(event HookEvent) error {
+			events = append(events, event)
+
+			return nil
+		}, Warn, Error)
+
+		logger := NewHookLogger(WithMinLevel(testLogger, Info), HookLoggerConfig{}, hook)
+
+		ctx := context.WithValue(context.Background(), contextKey("key"), "value")
+
+		logger.Info("info")
+		logger.Warn("warn", map[string]interface{}{"key": "value"})
+		logger.ErrorContext(ctx, "error")
+
+		if want, have := 3, testLogger.Count(); want != have {
+			t.Fatalf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if want, have := 2, len(events); want != have {
+			t.Fatalf("unexpected number of hook events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if want, have := "warn", events[0].Message; want != have {
+			t.Errorf("unexpected message\nexpected: %s\nactual:   %s", want, have)
+		}
+
+		if want, have := "value", events[0].Fields["key"]; want != have {
+			t.Errorf("unexpected field value\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if events[0].Context == nil {
+			t.Error("context should not be nil")
+		}
+
+		if want, have := Error, events[1].Level; want != have {
+			t.Errorf("unexpected level\nexpected: %s\nactual:   %s", want, have)
+		}
+
+		if want, have := "value", events[1].Context.Value(contextKey("key")); want != have {
+			t.Errorf("unexpected context value\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("DisabledLevel", func(t *testing.T) {
+		var fired bool
+
+		hook := NewHookFunc(func(event HookEvent) error {
+			fired = true
+
+			return nil
+		}, Debug)
+
+		logger := NewHookLogger(WithMinLevel(&TestLoggerFacade{}, Info), HookLoggerConfig{}, hook)
+
+		logger.Debug("message")
+
+		if fired {
+			t.Error("hooks should not be fired for disabled levels")
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		var errs []error
+
+		logger := NewHookLogger(testLogger, HookLoggerConfig{
+			ErrorHandler: func(_ Hook, err error) {
+				errs = append(errs, err)
+			},
+		})
+
+		var fired bool
+
+		logger.AddHook(NewHookFunc(func(event HookEvent) error { return errors.New("hook failed") }, Error))
+		logger.AddHook(NewHookFunc(func(event HookEvent) error { panic("hook panicked") }, Error))
+		logger.AddHook(NewHookFunc(func(event HookEvent) error {
+			fired = true
+
+			return nil
+		}, Error))
+
+		logger.Error("message")
+
+		if want, have := 1, testLogger.Count(); want != have {
+			t.Fatalf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if !fired {
+			t.Error("hooks should be fired after a failing hook")
+		}
+
+		if want, have := 2, len(errs); want != have {
+			t.Fatalf("unexpected number of errors\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if want, have := "hook failed", errs[0].Error(); want != have {
+			t.Errorf("unexpected error\nexpected: %s\nactual:   %s", want, have)
+		}
+
+		if want, have := "hook panicked: hook panicked", errs[1].Error(); want != have {
+			t.Errorf("unexpected error\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		logger := NewHookLogger(&TestLoggerFacade{}, HookLoggerConfig{})
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				logger.AddHook(NewHookFunc(func(event HookEvent) error { return nil }, Info))
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				logger.Info("message")
+			}()
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				testLogger := &TestLoggerFacade{}
+
+				hook := NewHookFunc(func(event HookEvent) error { return nil }, Trace, Debug, Info, Warn, Error)
+
+				return NewHookLogger(WithMinLevel(testLogger, level), HookLoggerConfig{}, hook), testLogger
+			},
+		}
+
+		suite.Run(t)
+	})
+}
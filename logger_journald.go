@@ -0,0 +1,237 @@
+package logur
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// JournaldLoggerConfig configures a JournaldLogger.
+type JournaldLoggerConfig struct {
+	// SocketPath is the path of the journald socket (defaults to /run/systemd/journal/socket).
+	SocketPath string
+
+	// SyslogIdentifier identifies the application (defaults to the name of the executable).
+	SyslogIdentifier string
+
+	// ErrorHandler is called when an entry cannot be sent.
+	// Errors are ignored by default.
+	ErrorHandler func(err error)
+}
+
+// JournaldLogger sends log events to journald using its native protocol.
+//
+// Fields become journal fields: their names are uppercased and characters other than A-Z, 0-9 and _ are replaced by _.
+// Fields colliding with the ones written by the logger (MESSAGE, PRIORITY and SYSLOG_IDENTIFIER) are prefixed with FIELD_.
+// Levels are mapped to syslog priorities: Error to err, Warn to warning, Info to info, Debug and Trace to debug.
+//
+// Entries too large for a single datagram are written to a temporary file
+// and its file descriptor is passed to journald (on Linux).
+//
+// The JournaldLogger is safe for concurrent use.
+type JournaldLogger struct {
+	config JournaldLoggerConfig
+
+	conn *net.UnixConn
+	addr *net.UnixAddr
+}
+
+// NewJournaldLogger returns a new JournaldLogger.
+func NewJournaldLogger(config JournaldLoggerConfig) (*JournaldLogger, error) {
+	if config.SocketPath == "" {
+		config.SocketPath = "/run/systemd/journal/socket"
+	}
+
+	if config.SyslogIdentifier == "" {
+		config.SyslogIdentifier = filepath.Base(os.Args[0])
+	}
+
+	// An unconnected socket keeps working when journald is restarted
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &JournaldLogger{
+		config: config,
+		conn:   conn,
+		addr:   &net.UnixAddr{Name: config.SocketPath, Net: "unixgram"},
+	}, nil
+}
+
+// Close closes the socket.
+func (l *JournaldLogger) Close() error {
+	return l.conn.Close()
+}
+
+// Trace implements the Logger interface.
+func (l *JournaldLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *JournaldLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *JournaldLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *JournaldLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *JournaldLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *JournaldLogger) TraceContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *JournaldLogger) DebugContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *JournaldLogger) InfoContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *JournaldLogger) WarnContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *JournaldLogger) ErrorContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+func (l *JournaldLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	buf := outputBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	defer outputBufferPool.Put(buf)
+
+	writeJournaldField(buf, "MESSAGE", msg)
+	writeJournaldField(buf, "PRIORITY", strconv.Itoa(syslogSeverity(level)))
+	writeJournaldField(buf, "SYSLOG_IDENTIFIER", l.config.SyslogIdentifier)
+
+	if len(fields) > 0 {
+		for _, key := range sortedKeys(fields[0]) {
+			name := journaldFieldName(key)
+
+			switch name {
+			case "MESSAGE", "PRIORITY", "SYSLOG_IDENTIFIER":
+				name = "FIELD_" + name
+			}
+
+			writeJournaldField(buf, name, logfmtValue(fields[0][key]))
+		}
+	}
+
+	if err := l.send(buf.Bytes()); err != nil && l.config.ErrorHandler != nil {
+		l.config.ErrorHandler(err)
+	}
+}
+
+func (l *JournaldLogger) send(entry []byte) error {
+	_, _, err := l.conn.WriteMsgUnix(entry, nil, l.addr)
+	if err == nil {
+		return nil
+	}
+
+	if !isErrno(err, syscall.EMSGSIZE) && !isErrno(err, syscall.ENOBUFS) {
+		return err
+	}
+
+	// The entry is too large for a datagram: pass it in a file instead
+	return sendJournaldFile(l.conn, l.addr, entry)
+}
+
+// writeJournaldField writes a field in the journald native format.
+// Values containing new lines are written in the binary format (name, length and raw value).
+func writeJournaldField(buf *bytes.Buffer, name string, value string) {
+	buf.WriteString(name)
+
+	if strings.IndexByte(value, '\n') == -1 {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+
+		return
+	}
+
+	var length [8]byte
+
+	binary.LittleEndian.PutUint64(length[:], uint64(len(value)))
+
+	buf.WriteByte('\n')
+	buf.Write(length[:])
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journaldFieldName converts a key to a valid journal field name:
+// uppercase letters, digits and underscores, not starting with an underscore or a digit, at most 64 characters.
+func journaldFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+
+		default:
+			return '_'
+		}
+	}, key)
+
+	// Fields starting with an underscore are trusted fields set by journald
+	name = strings.TrimLeft(name, "_")
+
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "FIELD_" + name
+	}
+
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return name
+}
+
+// isErrno checks if an error returned by the net package is caused by a specific system call error.
+func isErrno(err error, errno syscall.Errno) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case syscall.Errno:
+			return e == errno
+
+		case *net.OpError:
+			err = e.Err
+
+		case *os.SyscallError:
+			err = e.Err
+
+		default:
+			return false
+		}
+	}
+
+	return false
+}
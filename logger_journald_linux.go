@@ -0,0 +1,38 @@
+//go:build linux
+// +build linux
+
+package logur
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"syscall"
+)
+
+// sendJournaldFile writes an entry to an unlinked temporary file and passes its file descriptor to journald.
+func sendJournaldFile(conn *net.UnixConn, addr *net.UnixAddr, entry []byte) error {
+	// Prefer a memory backed file system
+	dir := "/dev/shm"
+	if _, err := os.Stat(dir); err != nil {
+		dir = ""
+	}
+
+	file, err := ioutil.TempFile(dir, "logur-journal-")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := os.Remove(file.Name()); err != nil {
+		return err
+	}
+
+	if _, err := file.Write(entry); err != nil {
+		return err
+	}
+
+	_, _, err = conn.WriteMsgUnix(nil, syscall.UnixRights(int(file.Fd())), addr)
+
+	return err
+}
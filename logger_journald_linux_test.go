@@ -0,0 +1,75 @@
+//go:build linux
+// +build linux
+
+package logur_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	. "logur.dev/logur"
+)
+
+func TestJournaldLogger_LargeEntry(t *testing.T) {
+	conn, path, cleanup := newJournaldListener(t)
+	defer cleanup()
+
+	logger, err := NewJournaldLogger(JournaldLoggerConfig{SocketPath: path, SyslogIdentifier: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Close()
+
+	msg := strings.Repeat("a", 1<<20)
+
+	logger.Info(msg)
+
+	buf := make([]byte, 4096)
+	oob := make([]byte, syscall.CmsgSpace(4))
+
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 0 {
+		t.Fatalf("the entry should be passed in a file, got %d bytes", n)
+	}
+
+	messages, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fds, err := syscall.ParseUnixRights(&messages[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := os.NewFile(uintptr(fds[0]), "entry")
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := ioutil.ReadAll(io.NewSectionReader(file, 0, info.Size()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := parseJournaldEntry(t, entry)
+
+	if want, have := msg, fields["MESSAGE"][0]; want != have {
+		t.Errorf("unexpected message length\nexpected: %d\nactual:   %d", len(want), len(have))
+	}
+
+	if want, have := "6", fields["PRIORITY"][0]; want != have {
+		t.Errorf("unexpected priority\nexpected: %s\nactual:   %s", want, have)
+	}
+}
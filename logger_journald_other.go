@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package logur
+
+import (
+	"errors"
+	"net"
+)
+
+// sendJournaldFile is only supported on Linux.
+func sendJournaldFile(_ *net.UnixConn, _ *net.UnixAddr, _ []byte) error {
+	return errors.New("journald: entry is too large")
+}
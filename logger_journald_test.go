@@ -0,0 +1,114 @@
+package logur_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+)
+
+// parseJournaldEntry parses an entry in the journald native format.
+func parseJournaldEntry(t *testing.T, entry []byte) map[string][]string {
+	t.Helper()
+
+	fields := make(map[string][]string)
+
+	for len(entry) > 0 {
+		i := bytes.IndexAny(entry, "=\n")
+		if i < 0 {
+			t.Fatalf("invalid entry: %q", entry)
+		}
+
+		name := string(entry[:i])
+
+		if entry[i] == '=' {
+			end := bytes.IndexByte(entry, '\n')
+			fields[name] = append(fields[name], string(entry[i+1:end]))
+			entry = entry[end+1:]
+
+			continue
+		}
+
+		length := int(binary.LittleEndian.Uint64(entry[i+1 : i+9]))
+		fields[name] = append(fields[name], string(entry[i+9:i+9+length]))
+		entry = entry[i+9+length+1:]
+	}
+
+	return fields
+}
+
+func newJournaldListener(t *testing.T) (*net.UnixConn, string, func()) {
+	t.Helper()
+
+	dir, cleanup := newTempDir(t)
+
+	path := filepath.Join(dir, "socket")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	return conn, path, func() {
+		_ = conn.Close()
+		cleanup()
+	}
+}
+
+func TestJournaldLogger(t *testing.T) {
+	conn, path, cleanup := newJournaldListener(t)
+	defer cleanup()
+
+	logger, err := NewJournaldLogger(JournaldLoggerConfig{SocketPath: path, SyslogIdentifier: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Close()
+
+	logger.Warn("multi\nline", map[string]interface{}{
+		"key":        "value",
+		"request-id": 1234,
+		"_trusted":   true,
+		"1st":        "first",
+		"priority":   0,
+		"message":    "override",
+	})
+
+	buf := make([]byte, 4096)
+
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := parseJournaldEntry(t, buf[:n])
+
+	expected := map[string]string{
+		"MESSAGE":           "multi\nline",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "app",
+		"KEY":               "value",
+		"REQUEST_ID":        "1234",
+		"TRUSTED":           "true",
+		"FIELD_1ST":         "first",
+		"FIELD_PRIORITY":    "0",
+		"FIELD_MESSAGE":     "override",
+	}
+
+	if want, have := len(expected), len(fields); want != have {
+		t.Errorf("unexpected number of fields\nexpected: %d\nactual:   %d (%v)", want, have, fields)
+	}
+
+	for name, value := range expected {
+		if want, have := []string{value}, fields[name]; len(have) != 1 || want[0] != have[0] {
+			t.Errorf("unexpected value of %s\nexpected: %q\nactual:   %q", name, want, have)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package logur
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// NewJSONLogger returns a new logger that writes log events as JSON objects (one per line) to w.
+//
+// A time.Time field under the time key overrides the timestamp of the event (see TimeKey).
+// The returned logger also implements LevelEnabler.
+func NewJSONLogger(w io.Writer, opts ...OutputOption) LoggerFacade {
+	return newOutputLogger(w, jsonEncoder{}, opts)
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) encode(buf *bytes.Buffer, event outputEvent, options outputOptions) {
+	buf.WriteByte('{')
+
+	if options.timeKey != "" {
+		writeJSONString(buf, options.timeKey)
+		buf.WriteByte(':')
+		writeJSONString(buf, event.time.Format(options.timeLayout))
+		buf.WriteByte(',')
+	}
+
+	writeJSONString(buf, options.levelKey)
+	buf.WriteByte(':')
+	writeJSONString(buf, event.level.String())
+	buf.WriteByte(',')
+
+	writeJSONString(buf, options.messageKey)
+	buf.WriteByte(':')
+	writeJSONString(buf, event.msg)
+
+	for _, key := range sortedKeys(event.fields) {
+		buf.WriteByte(',')
+		writeJSONString(buf, outputFieldKey(key, options))
+		buf.WriteByte(':')
+		writeJSONValue(buf, event.fields[key])
+	}
+
+	buf.WriteString("}\n")
+}
+
+// writeJSONValue writes an arbitrary value as JSON.
+// Values that cannot be marshaled are written as their string representation.
+func writeJSONValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+
+	case string:
+		writeJSONString(buf, v)
+
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+
+	case int:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+
+	case int8:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+
+	case int16:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+
+	case int32:
+		buf.WriteString(strconv.FormatInt(int64(v), 10))
+
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+
+	case uint:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+
+	case uint8:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+
+	case uint16:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+
+	case uint32:
+		buf.WriteString(strconv.FormatUint(uint64(v), 10))
+
+	case uint64:
+		buf.WriteString(strconv.FormatUint(v, 10))
+
+	case float32:
+		writeJSONFloat(buf, float64(v), 32)
+
+	case float64:
+		writeJSONFloat(buf, v, 64)
+
+	case json.Marshaler:
+		writeJSONMarshaled(buf, v)
+
+	case error:
+		writeJSONString(buf, v.Error())
+
+	default:
+		writeJSONMarshaled(buf, v)
+	}
+}
+
+func writeJSONFloat(buf *bytes.Buffer, f float64, bitSize int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		writeJSONString(buf, strconv.FormatFloat(f, 'g', -1, bitSize))
+
+		return
+	}
+
+	buf.WriteString(strconv.FormatFloat(f, 'g', -1, bitSize))
+}
+
+func writeJSONMarshaled(buf *bytes.Buffer, value interface{}) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		writeJSONString(buf, fmt.Sprintf("%+v", value))
+
+		return
+	}
+
+	buf.Write(b)
+}
+
+const jsonHex = "0123456789abcdef"
+
+// writeJSONString writes a quoted JSON string without escaping HTML characters.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(c)
+
+			case c == '\n':
+				buf.WriteString(`\n`)
+
+			case c == '\r':
+				buf.WriteString(`\r`)
+
+			case c == '\t':
+				buf.WriteString(`\t`)
+
+			case c < 0x20:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(jsonHex[c>>4])
+				buf.WriteByte(jsonHex[c&0xF])
+
+			default:
+				buf.WriteByte(c)
+			}
+
+			i++
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.WriteString(`\ufffd`)
+		} else {
+			buf.WriteString(s[i : i+size])
+		}
+
+		i += size
+	}
+
+	buf.WriteByte('"')
+}
@@ -0,0 +1,148 @@
+package logur_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+func parseJSONEvents(t *testing.T, buf *bytes.Buffer) []LogEvent {
+	t.Helper()
+
+	var events []LogEvent
+
+	scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+
+	for scanner.Scan() {
+		var fields map[string]interface{}
+
+		if err := json.Unmarshal(scanner.Bytes(), &fields); err != nil {
+			t.Fatalf("invalid JSON line %q: %s", scanner.Text(), err)
+		}
+
+		level, _ := ParseLevel(fields["level"].(string))
+		msg := fields["msg"].(string)
+
+		delete(fields, "level")
+		delete(fields, "msg")
+
+		events = append(events, LogEvent{
+			Line:   msg,
+			Level:  level,
+			Fields: fields,
+		})
+	}
+
+	return events
+}
+
+func TestJSONLogger(t *testing.T) {
+	t.Run("Output", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := NewJSONLogger(&buf, TimeKey(""))
+
+		logger.Info("message", map[string]interface{}{
+			"key2":  "value<>",
+			"key1":  1,
+			"error": errors.New("something went wrong"),
+			"msg":   "collision",
+		})
+
+		const expected = `{"level":"info","msg":"message","error":"something went wrong",` +
+			`"key1":1,"key2":"value<>","fields.msg":"collision"}` + "\n"
+
+		if want, have := expected, buf.String(); want != have {
+			t.Errorf("unexpected output\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := NewJSONLogger(
+			&buf,
+			TimeKey("ts"),
+			TimeLayout(time.RFC3339),
+			LevelKey("severity"),
+			MessageKey("message"),
+		)
+
+		logger.Warn("message")
+
+		var event map[string]interface{}
+
+		if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, have := "warn", event["severity"]; want != have {
+			t.Errorf("unexpected level\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := "message", event["message"]; want != have {
+			t.Errorf("unexpected message\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if _, err := time.Parse(time.RFC3339, event["ts"].(string)); err != nil {
+			t.Errorf("invalid timestamp: %s", err)
+		}
+	})
+
+	t.Run("TimeField", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := NewJSONLogger(&buf, TimeLayout(time.RFC3339))
+
+		logger.Info("message", map[string]interface{}{"time": time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)})
+		logger.Info("message", map[string]interface{}{"time": "collision"})
+
+		lines := strings.SplitAfter(buf.String(), "\n")
+
+		// Any time.Time value under the time key is used as the timestamp
+		if want, have := `{"time":"2020-01-02T03:04:05Z","level":"info","msg":"message"}`+"\n", lines[0]; want != have {
+			t.Errorf("unexpected output\nexpected: %s\nactual:   %s", want, have)
+		}
+
+		if want, have := `,"level":"info","msg":"message","fields.time":"collision"}`+"\n", lines[1]; !strings.HasSuffix(have, want) {
+			t.Errorf("unexpected output\nexpected suffix: %s\nactual:          %s", want, have)
+		}
+	})
+
+	t.Run("Escaping", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := NewJSONLogger(&buf, TimeKey(""))
+
+		logger.Info("line\n\"quoted\"\x01\xff")
+
+		events := parseJSONEvents(t, &buf)
+
+		if want, have := "line\n\"quoted\"\x01\ufffd", events[0].Line; want != have {
+			t.Errorf("unexpected message\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				var buf bytes.Buffer
+
+				logger := NewJSONLogger(&buf, TimeKey(""), MinLevel(level))
+
+				return logger, conformance.TestLoggerFunc(func() []LogEvent {
+					return parseJSONEvents(t, &buf)
+				})
+			},
+		}
+
+		suite.Run(t)
+	})
+}
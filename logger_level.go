@@ -0,0 +1,152 @@
+package logur
+
+import (
+	"context"
+)
+
+// WithMinLevel returns a new logger that drops every event below the given level.
+//
+// The returned logger also implements LevelEnabler.
+// If the underlying logger implements LevelEnabler as well, both of them have to enable a level.
+func WithMinLevel(logger Logger, level Level) LoggerFacade {
+	return newLevelLogger(logger, minLevelEnabler(level))
+}
+
+// WithLevelEnabler returns a new logger that drops every event not enabled by levelEnabler.
+//
+// Combined with AtomicLevel it allows changing the minimum level of a logger at runtime.
+// The returned logger also implements LevelEnabler.
+// If the underlying logger implements LevelEnabler as well, both of them have to enable a level.
+func WithLevelEnabler(logger Logger, levelEnabler LevelEnabler) LoggerFacade {
+	return newLevelLogger(logger, levelEnabler)
+}
+
+func newLevelLogger(logger Logger, levelEnabler LevelEnabler) *levelLogger {
+	l := &levelLogger{
+		logger:       ensureLoggerFacade(logger),
+		levelEnabler: levelEnabler,
+	}
+
+	if levelEnabler, ok := logger.(LevelEnabler); ok {
+		l.parentLevelEnabler = levelEnabler
+	}
+
+	return l
+}
+
+// minLevelEnabler enables every level above (and including) a minimum level.
+type minLevelEnabler Level
+
+func (l minLevelEnabler) LevelEnabled(level Level) bool {
+	return level >= Level(l)
+}
+
+// levelLogger drops events that are not enabled by a LevelEnabler.
+type levelLogger struct {
+	logger             LoggerFacade
+	levelEnabler       LevelEnabler
+	parentLevelEnabler LevelEnabler
+}
+
+// Trace implements the Logger interface.
+func (l *levelLogger) Trace(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Trace) {
+		return
+	}
+
+	l.logger.Trace(msg, fields...)
+}
+
+// Debug implements the Logger interface.
+func (l *levelLogger) Debug(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Debug) {
+		return
+	}
+
+	l.logger.Debug(msg, fields...)
+}
+
+// Info implements the Logger interface.
+func (l *levelLogger) Info(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Info) {
+		return
+	}
+
+	l.logger.Info(msg, fields...)
+}
+
+// Warn implements the Logger interface.
+func (l *levelLogger) Warn(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Warn) {
+		return
+	}
+
+	l.logger.Warn(msg, fields...)
+}
+
+// Error implements the Logger interface.
+func (l *levelLogger) Error(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Error) {
+		return
+	}
+
+	l.logger.Error(msg, fields...)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *levelLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Trace) {
+		return
+	}
+
+	l.logger.TraceContext(ctx, msg, fields...)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *levelLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Debug) {
+		return
+	}
+
+	l.logger.DebugContext(ctx, msg, fields...)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *levelLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Info) {
+		return
+	}
+
+	l.logger.InfoContext(ctx, msg, fields...)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *levelLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Warn) {
+		return
+	}
+
+	l.logger.WarnContext(ctx, msg, fields...)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *levelLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Error) {
+		return
+	}
+
+	l.logger.ErrorContext(ctx, msg, fields...)
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *levelLogger) LevelEnabled(level Level) bool {
+	if !l.levelEnabler.LevelEnabled(level) {
+		return false
+	}
+
+	if l.parentLevelEnabler != nil {
+		return l.parentLevelEnabler.LevelEnabled(level)
+	}
+
+	return true
+}
@@ -0,0 +1,73 @@
+package logur_test
+
+import (
+	"context"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+	"logur.dev/logur/logtesting"
+)
+
+func TestWithMinLevel(t *testing.T) {
+	t.Run("Filter", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithMinLevel(testLogger, Warn)
+
+		logger.Info("message")
+		logger.InfoContext(context.Background(), "message")
+		logger.Debug("message")
+		logger.Warn("warning")
+		logger.ErrorContext(context.Background(), "error")
+
+		if want, have := 2, testLogger.Count(); want != have {
+			t.Fatalf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		logtesting.AssertLogEventsEqual(t, LogEvent{Line: "warning", Level: Warn}, testLogger.Events()[0])
+		logtesting.AssertLogEventsEqual(t, LogEvent{Line: "error", Level: Error}, testLogger.Events()[1])
+	})
+
+	t.Run("ParentLevelEnabler", func(t *testing.T) {
+		var enabled []Level
+
+		logger := WithMinLevel(WithMinLevel(&TestLogger{}, Warn), Debug).(LevelEnabler)
+
+		for _, level := range Levels() {
+			if logger.LevelEnabled(level) {
+				enabled = append(enabled, level)
+			}
+		}
+
+		if want, have := 2, len(enabled); want != have || enabled[0] != Warn {
+			t.Errorf("unexpected enabled levels: %v", enabled)
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		t.Run("Logger", func(t *testing.T) {
+			suite := conformance.TestSuite{
+				LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+					logger := &TestLogger{}
+
+					return WithMinLevel(logger, level), logger
+				},
+			}
+
+			suite.Run(t)
+		})
+
+		t.Run("Facade", func(t *testing.T) {
+			suite := conformance.TestSuite{
+				LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+					logger := &TestLoggerFacade{}
+
+					return WithMinLevel(logger, level), logger
+				},
+			}
+
+			suite.Run(t)
+		})
+	})
+}
@@ -0,0 +1,100 @@
+package logur
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NewLogfmtLogger returns a new logger that writes log events in logfmt format (one per line) to w.
+//
+// Fields are written in a stable (lexical) order after the timestamp, level and message.
+// The timestamp is taken from a time.Time field under the time key when there is one (see TimeKey).
+// The returned logger also implements LevelEnabler.
+func NewLogfmtLogger(w io.Writer, opts ...OutputOption) LoggerFacade {
+	return newOutputLogger(w, logfmtEncoder{}, opts)
+}
+
+type logfmtEncoder struct{}
+
+func (logfmtEncoder) encode(buf *bytes.Buffer, event outputEvent, options outputOptions) {
+	if options.timeKey != "" {
+		writeLogfmtKeyValue(buf, options.timeKey, event.time.Format(options.timeLayout))
+		buf.WriteByte(' ')
+	}
+
+	writeLogfmtKeyValue(buf, options.levelKey, event.level.String())
+	buf.WriteByte(' ')
+	writeLogfmtKeyValue(buf, options.messageKey, event.msg)
+
+	for _, key := range sortedKeys(event.fields) {
+		buf.WriteByte(' ')
+		writeLogfmtKeyValue(buf, outputFieldKey(key, options), logfmtValue(event.fields[key]))
+	}
+
+	buf.WriteByte('\n')
+}
+
+func writeLogfmtKeyValue(buf *bytes.Buffer, key string, value string) {
+	writeLogfmtKey(buf, key)
+	buf.WriteByte('=')
+	writeLogfmtString(buf, value)
+}
+
+// writeLogfmtKey writes a key replacing characters that are invalid in a logfmt key with underscores.
+func writeLogfmtKey(buf *bytes.Buffer, key string) {
+	if key == "" {
+		buf.WriteByte('_')
+
+		return
+	}
+
+	for _, r := range key {
+		if logfmtNeedsQuoting(r) {
+			buf.WriteByte('_')
+
+			continue
+		}
+
+		buf.WriteRune(r)
+	}
+}
+
+// writeLogfmtString writes a value, quoting and escaping it if necessary.
+func writeLogfmtString(buf *bytes.Buffer, s string) {
+	if s == "" || strings.IndexFunc(s, logfmtNeedsQuoting) != -1 {
+		buf.WriteString(strconv.Quote(s))
+
+		return
+	}
+
+	buf.WriteString(s)
+}
+
+func logfmtNeedsQuoting(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r)
+}
+
+// logfmtValue converts an arbitrary value to its logfmt string representation.
+func logfmtValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+
+	case string:
+		return v
+
+	case error:
+		return v.Error()
+
+	case fmt.Stringer:
+		return v.String()
+
+	default:
+		return fmt.Sprintf("%+v", v)
+	}
+}
@@ -0,0 +1,163 @@
+package logur_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+// parseLogfmtLine parses a single logfmt line produced by the logfmt logger.
+func parseLogfmtLine(t *testing.T, line string) map[string]string {
+	t.Helper()
+
+	pairs := make(map[string]string)
+
+	for line != "" {
+		i := strings.IndexByte(line, '=')
+		if i < 0 {
+			t.Fatalf("invalid logfmt line: %q", line)
+		}
+
+		key := line[:i]
+		line = line[i+1:]
+
+		var value string
+
+		if strings.HasPrefix(line, `"`) {
+			end := 1
+			for ; end < len(line) && line[end] != '"'; end++ {
+				if line[end] == '\\' {
+					end++
+				}
+			}
+
+			v, err := strconv.Unquote(line[:end+1])
+			if err != nil {
+				t.Fatalf("invalid quoted value %q: %s", line[:end+1], err)
+			}
+
+			value = v
+			line = line[end+1:]
+		} else {
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				end = len(line)
+			}
+
+			value = line[:end]
+			line = line[end:]
+		}
+
+		pairs[key] = value
+		line = strings.TrimPrefix(line, " ")
+	}
+
+	return pairs
+}
+
+func TestLogfmtLogger(t *testing.T) {
+	t.Run("Output", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := NewLogfmtLogger(&buf, TimeKey(""))
+
+		logger.Info("some message", map[string]interface{}{
+			"zkey":    "value",
+			"akey":    1,
+			"error":   errors.New(`something "bad" happened`),
+			"empty":   "",
+			"nil":     nil,
+			"eq":      "a=b",
+			"bad key": "value",
+			"level":   "collision",
+		})
+
+		const expected = `level=info msg="some message" akey=1 bad_key=value empty="" eq="a=b" ` +
+			`error="something \"bad\" happened" fields.level=collision nil=null zkey=value` + "\n"
+
+		if want, have := expected, buf.String(); want != have {
+			t.Errorf("unexpected output\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("StableOrder", func(t *testing.T) {
+		fields := map[string]interface{}{}
+		for _, key := range []string{"c", "a", "e", "b", "d", "f", "g", "h"} {
+			fields[key] = key
+		}
+
+		var first string
+
+		for i := 0; i < 10; i++ {
+			var buf bytes.Buffer
+
+			NewLogfmtLogger(&buf, TimeKey("")).Info("message", fields)
+
+			if i == 0 {
+				first = buf.String()
+
+				continue
+			}
+
+			if buf.String() != first {
+				t.Fatalf("output is not deterministic\nfirst: %s\nlater: %s", first, buf.String())
+			}
+		}
+	})
+
+	t.Run("Escaping", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := NewLogfmtLogger(&buf, TimeKey(""))
+
+		logger.Info("line\n\ttab\x00")
+
+		pairs := parseLogfmtLine(t, strings.TrimSuffix(buf.String(), "\n"))
+
+		if want, have := "line\n\ttab\x00", pairs["msg"]; want != have {
+			t.Errorf("unexpected message\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				var buf bytes.Buffer
+
+				logger := NewLogfmtLogger(&buf, TimeKey(""), MinLevel(level))
+
+				return logger, conformance.TestLoggerFunc(func() []LogEvent {
+					var events []LogEvent
+
+					scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+
+					for scanner.Scan() {
+						pairs := parseLogfmtLine(t, scanner.Text())
+
+						level, _ := ParseLevel(pairs["level"])
+						event := LogEvent{Line: pairs["msg"], Level: level, Fields: map[string]interface{}{}}
+
+						delete(pairs, "level")
+						delete(pairs, "msg")
+
+						for key, value := range pairs {
+							event.Fields[key] = value
+						}
+
+						events = append(events, event)
+					}
+
+					return events
+				})
+			},
+		}
+
+		suite.Run(t)
+	})
+}
@@ -0,0 +1,688 @@
+package logur
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LokiLoggerConfig configures a LokiLogger.
+type LokiLoggerConfig struct {
+	// URL is the push API endpoint of Loki (eg. http://localhost:3100/loki/api/v1/push).
+	URL string
+
+	// TenantID is sent in the X-Scope-OrgID header (if any).
+	TenantID string
+
+	// Labels are added to every stream.
+	Labels map[string]string
+
+	// LabelKeys are the keys of fields turned into stream labels.
+	// These fields are removed from the log line.
+	LabelKeys []string
+
+	// LevelLabel is the name of the level label (defaults to "level").
+	LevelLabel string
+
+	// MessageKey is the key of the message in log lines (defaults to "msg").
+	MessageKey string
+
+	// BatchSize is the maximum size of log lines in a batch in bytes (defaults to 1 MiB).
+	BatchSize int
+
+	// BatchWait is the maximum time events are batched for (defaults to 1 second).
+	BatchWait time.Duration
+
+	// QueueSize is the maximum number of events waiting to be batched (defaults to 1024).
+	QueueSize int
+
+	// BlockTimeout is the maximum time a logging call waits for room in the queue when it is full.
+	// Events are dropped immediately by default.
+	BlockTimeout time.Duration
+
+	// Compress enables gzip compression of requests.
+	Compress bool
+
+	// MaxRetries is the maximum number of retries when pushing fails (defaults to 5, -1 disables retries).
+	// Requests are retried on connection errors, 429 and 5xx responses.
+	MaxRetries int
+
+	// MinBackoff is the wait time before the first retry (defaults to 100 milliseconds).
+	// It is doubled for every subsequent retry and jittered.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum wait time between retries (defaults to 10 seconds).
+	MaxBackoff time.Duration
+
+	// Timeout limits a single request (defaults to 10 seconds).
+	// It is ignored when a custom client is used.
+	Timeout time.Duration
+
+	// Client is used to send requests (defaults to a new client with Timeout).
+	Client *http.Client
+
+	// Clock is used to timestamp events (defaults to the system clock).
+	Clock Clock
+
+	// ErrorHandler is called when events are dropped or cannot be pushed (after every retry failed).
+	// Errors are ignored by default.
+	ErrorHandler func(err error)
+}
+
+// ErrLokiQueueFull is reported when an event is dropped because the queue of a LokiLogger is full.
+var ErrLokiQueueFull = errors.New("loki: queue is full, event dropped")
+
+// ErrLokiLoggerClosed is returned when an already closed LokiLogger is flushed.
+var ErrLokiLoggerClosed = errors.New("loki: logger is closed")
+
+// LokiLogger pushes log events to Grafana Loki.
+//
+// Every event belongs to a stream identified by its labels: the configured static labels,
+// the level and the fields listed in LabelKeys.
+// The rest of the event is written to the log line in logfmt format.
+// Fields colliding with the message key are prefixed with "fields.".
+//
+// Events are queued and batched on a separate goroutine,
+// so logging calls block for at most BlockTimeout (when the queue is full).
+//
+// Call Close to push the remaining events and stop the worker goroutine.
+type LokiLogger struct {
+	config LokiLoggerConfig
+
+	labelKeys map[string]bool
+
+	queue   chan lokiEntry
+	closing chan struct{}
+	done    chan struct{}
+
+	// mu guards closed, it is never held while waiting for room in the queue
+	mu     sync.RWMutex
+	closed bool
+
+	// senders tracks callers that may still put events into the queue
+	senders sync.WaitGroup
+
+	// rand jitters retries (it is only used by the worker goroutine, randMu guards it nevertheless)
+	rand   *rand.Rand
+	randMu sync.Mutex
+}
+
+type lokiEntry struct {
+	labels map[string]string
+	time   time.Time
+	line   string
+
+	// flushed receives the result of pushing the batch (only set for flush markers)
+	flushed chan error
+}
+
+type lokiStream struct {
+	labels map[string]string
+	values []lokiEntry
+}
+
+// NewLokiLogger returns a new LokiLogger and starts its worker goroutine.
+func NewLokiLogger(config LokiLoggerConfig) *LokiLogger {
+	if config.LevelLabel == "" {
+		config.LevelLabel = "level"
+	}
+
+	if config.MessageKey == "" {
+		config.MessageKey = "msg"
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1 << 20
+	}
+
+	if config.BatchWait == 0 {
+		config.BatchWait = time.Second
+	}
+
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1024
+	}
+
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 5
+	}
+
+	if config.MinBackoff == 0 {
+		config.MinBackoff = 100 * time.Millisecond
+	}
+
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = 10 * time.Second
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+
+	if config.Clock == nil {
+		config.Clock = SystemClock()
+	}
+
+	l := &LokiLogger{
+		config:    config,
+		labelKeys: make(map[string]bool, len(config.LabelKeys)),
+		queue:     make(chan lokiEntry, config.QueueSize),
+		closing:   make(chan struct{}),
+		done:      make(chan struct{}),
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())), // nolint: gosec
+	}
+
+	for _, key := range config.LabelKeys {
+		l.labelKeys[key] = true
+	}
+
+	go l.run()
+
+	return l
+}
+
+// Flush waits until every event queued before the call is pushed to Loki
+// or the context is canceled.
+func (l *LokiLogger) Flush(ctx context.Context) error {
+	flushed := make(chan error, 1)
+
+	if !l.startSending() {
+		return ErrLokiLoggerClosed
+	}
+
+	select {
+	case l.queue <- lokiEntry{flushed: flushed}:
+		l.senders.Done()
+
+	case <-l.closing:
+		l.senders.Done()
+
+		return ErrLokiLoggerClosed
+
+	case <-ctx.Done():
+		l.senders.Done()
+
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-flushed:
+		return err
+
+	case <-l.done:
+		// The worker pushes queued events (including the flush marker) before it stops
+		select {
+		case err := <-flushed:
+			return err
+
+		default:
+			return nil
+		}
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting new events and waits until the remaining events are pushed or the context is canceled.
+// Events logged after Close are dropped.
+func (l *LokiLogger) Close(ctx context.Context) error {
+	l.mu.Lock()
+
+	if !l.closed {
+		l.closed = true
+		close(l.closing)
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Trace implements the Logger interface.
+func (l *LokiLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *LokiLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *LokiLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *LokiLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *LokiLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *LokiLogger) TraceContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *LokiLogger) DebugContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *LokiLogger) InfoContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *LokiLogger) WarnContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *LokiLogger) ErrorContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+func (l *LokiLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	var f map[string]interface{}
+	if len(fields) > 0 {
+		f = fields[0]
+	}
+
+	labels := make(map[string]string, len(l.config.Labels)+len(l.config.LabelKeys)+1)
+
+	for name, value := range l.config.Labels {
+		labels[lokiLabelName(name)] = value
+	}
+
+	labels[lokiLabelName(l.config.LevelLabel)] = level.String()
+
+	buf := outputBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	defer outputBufferPool.Put(buf)
+
+	writeLogfmtKeyValue(buf, l.config.MessageKey, msg)
+
+	for _, key := range sortedKeys(f) {
+		if l.labelKeys[key] {
+			labels[lokiLabelName(key)] = logfmtValue(f[key])
+
+			continue
+		}
+
+		name := key
+		if name == l.config.MessageKey {
+			name = "fields." + name
+		}
+
+		buf.WriteByte(' ')
+		writeLogfmtKeyValue(buf, name, logfmtValue(f[key]))
+	}
+
+	l.enqueue(lokiEntry{
+		labels: labels,
+		time:   l.config.Clock.Now(),
+		line:   buf.String(),
+	})
+}
+
+// startSending registers a caller putting events into the queue unless the logger is closed.
+func (l *LokiLogger) startSending() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		return false
+	}
+
+	l.senders.Add(1)
+
+	return true
+}
+
+func (l *LokiLogger) enqueue(entry lokiEntry) {
+	if !l.startSending() {
+		return
+	}
+	defer l.senders.Done()
+
+	select {
+	case l.queue <- entry:
+		return
+
+	default:
+	}
+
+	if l.config.BlockTimeout > 0 {
+		timer := time.NewTimer(l.config.BlockTimeout)
+		defer timer.Stop()
+
+		select {
+		case l.queue <- entry:
+			return
+
+		case <-l.closing:
+			// Events logged after Close are dropped
+			return
+
+		case <-timer.C:
+		}
+	}
+
+	l.handleError(ErrLokiQueueFull)
+}
+
+// run batches queued events and pushes a batch when it is full or too old.
+func (l *LokiLogger) run() {
+	defer close(l.done)
+
+	var (
+		streams map[string]*lokiStream
+		size    int
+		timer   *time.Timer
+		timeout <-chan time.Time
+	)
+
+	push := func() error {
+		if timer != nil {
+			timer.Stop()
+			timer, timeout = nil, nil
+		}
+
+		if len(streams) == 0 {
+			return nil
+		}
+
+		batch := streams
+		streams, size = nil, 0
+
+		return l.push(batch)
+	}
+
+	add := func(entry lokiEntry) {
+		if entry.flushed != nil {
+			entry.flushed <- push()
+
+			return
+		}
+
+		if streams == nil {
+			streams = make(map[string]*lokiStream)
+			timer = time.NewTimer(l.config.BatchWait)
+			timeout = timer.C
+		}
+
+		key := lokiStreamKey(entry.labels)
+
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{labels: entry.labels}
+			streams[key] = stream
+		}
+
+		stream.values = append(stream.values, entry)
+		size += len(entry.line)
+
+		if size >= l.config.BatchSize {
+			if err := push(); err != nil {
+				l.handleError(err)
+			}
+		}
+	}
+
+	for {
+		select {
+		case entry := <-l.queue:
+			add(entry)
+
+		case <-timeout:
+			if err := push(); err != nil {
+				l.handleError(err)
+			}
+
+		case <-l.closing:
+			// Wait for blocked callers to give up, then drain the queue
+			l.senders.Wait()
+
+			for len(l.queue) > 0 {
+				add(<-l.queue)
+			}
+
+			if err := push(); err != nil {
+				l.handleError(err)
+			}
+
+			return
+		}
+	}
+}
+
+// push sends a batch to Loki, retrying with jittered exponential backoff.
+func (l *LokiLogger) push(streams map[string]*lokiStream) error {
+	body := encodeLokiPushRequest(streams)
+
+	if l.config.Compress {
+		var compressed bytes.Buffer
+
+		w := gzip.NewWriter(&compressed)
+		_, _ = w.Write(body)
+		_ = w.Close()
+
+		body = compressed.Bytes()
+	}
+
+	backoff := l.config.MinBackoff
+
+	for retry := 0; ; retry++ {
+		retryable, err := l.send(body)
+		if err == nil {
+			return nil
+		}
+
+		if !retryable || l.config.MaxRetries < 0 || retry >= l.config.MaxRetries {
+			return err
+		}
+
+		// Wait between half and the full backoff
+		time.Sleep(backoff/2 + l.jitter(backoff/2))
+
+		backoff *= 2
+		if backoff > l.config.MaxBackoff {
+			backoff = l.config.MaxBackoff
+		}
+	}
+}
+
+// send sends a single push request and tells whether it should be retried when it fails.
+func (l *LokiLogger) send(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, l.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if l.config.Compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	if l.config.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", l.config.TenantID)
+	}
+
+	resp, err := l.config.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+		return false, nil
+	}
+
+	message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	err = fmt.Errorf("loki: unexpected response %s: %s", resp.Status, bytes.TrimSpace(message))
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode/100 == 5, err
+}
+
+// jitter returns a random duration between 0 and limit.
+func (l *LokiLogger) jitter(limit time.Duration) time.Duration {
+	l.randMu.Lock()
+	defer l.randMu.Unlock()
+
+	return time.Duration(l.rand.Int63n(int64(limit) + 1))
+}
+
+func (l *LokiLogger) handleError(err error) {
+	if l.config.ErrorHandler != nil {
+		l.config.ErrorHandler(err)
+	}
+}
+
+// encodeLokiPushRequest encodes streams in the JSON push format.
+func encodeLokiPushRequest(streams map[string]*lokiStream) []byte {
+	keys := make([]string, 0, len(streams))
+	for key := range streams {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+
+	buf.WriteString(`{"streams":[`)
+
+	for i, key := range keys {
+		stream := streams[key]
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		buf.WriteString(`{"stream":{`)
+
+		for j, name := range sortedLabelNames(stream.labels) {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+
+			writeJSONString(&buf, name)
+			buf.WriteByte(':')
+			writeJSONString(&buf, stream.labels[name])
+		}
+
+		buf.WriteString(`},"values":[`)
+
+		for j, entry := range stream.values {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+
+			buf.WriteString(`["`)
+			buf.WriteString(strconv.FormatInt(entry.time.UnixNano(), 10))
+			buf.WriteString(`",`)
+			writeJSONString(&buf, entry.line)
+			buf.WriteByte(']')
+		}
+
+		buf.WriteString(`]}`)
+	}
+
+	buf.WriteString(`]}`)
+
+	return buf.Bytes()
+}
+
+// lokiStreamKey returns a string identifying a stream by its labels.
+func lokiStreamKey(labels map[string]string) string {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, name := range sortedLabelNames(labels) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		buf.WriteString(name)
+		buf.WriteByte('=')
+		buf.WriteString(strconv.Quote(labels[name]))
+	}
+
+	buf.WriteByte('}')
+
+	return buf.String()
+}
+
+func sortedLabelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// lokiLabelName converts a key to a valid label name
+// (containing only letters, digits and underscores and not starting with a digit).
+func lokiLabelName(key string) string {
+	name := make([]byte, 0, len(key)+1)
+
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+			name = append(name, c)
+
+		case c >= '0' && c <= '9':
+			if i == 0 {
+				name = append(name, '_')
+			}
+
+			name = append(name, c)
+
+		default:
+			name = append(name, '_')
+		}
+	}
+
+	if len(name) == 0 {
+		return "_"
+	}
+
+	return string(name)
+}
@@ -0,0 +1,377 @@
+package logur_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+)
+
+type lokiPushRequest struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+}
+
+// lokiServer is a push API stand-in recording requests.
+type lokiServer struct {
+	*httptest.Server
+
+	requests chan lokiPushRequest
+	headers  chan http.Header
+
+	mu       sync.Mutex
+	statuses []int
+}
+
+// newLokiServer returns a new lokiServer responding with the given statuses (and 204 afterwards).
+func newLokiServer(t *testing.T, statuses ...int) *lokiServer {
+	t.Helper()
+
+	s := &lokiServer{
+		requests: make(chan lokiPushRequest, 100),
+		headers:  make(chan http.Header, 100),
+		statuses: statuses,
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			body = gz
+		}
+
+		var request lokiPushRequest
+
+		if err := json.NewDecoder(body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		s.requests <- request
+		s.headers <- r.Header
+
+		s.mu.Lock()
+		status := http.StatusNoContent
+
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		s.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+
+	return s
+}
+
+func (s *lokiServer) receive(t *testing.T) lokiPushRequest {
+	t.Helper()
+
+	select {
+	case request := <-s.requests:
+		<-s.headers
+
+		return request
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for request")
+
+		return lokiPushRequest{}
+	}
+}
+
+func newLokiTestConfig(url string) LokiLoggerConfig {
+	return LokiLoggerConfig{
+		URL:        url,
+		Labels:     map[string]string{"app": "test"},
+		LabelKeys:  []string{"component"},
+		BatchWait:  time.Hour,
+		MinBackoff: time.Millisecond,
+		Clock:      ClockFunc(func() time.Time { return time.Unix(1577934245, 6) }),
+	}
+}
+
+func TestLokiLogger(t *testing.T) {
+	t.Run("Push", func(t *testing.T) {
+		server := newLokiServer(t)
+		defer server.Close()
+
+		config := newLokiTestConfig(server.URL)
+		config.Compress = true
+		config.TenantID = "tenant"
+
+		logger := NewLokiLogger(config)
+		defer logger.Close(context.Background())
+
+		logger.Info("message 1", map[string]interface{}{"component": "db", "key": "value"})
+		logger.Error("message 2", map[string]interface{}{"component": "db", "msg": "collision"})
+		logger.Info("message 3", map[string]interface{}{"component": "db"})
+		logger.Info("message 4")
+
+		if err := logger.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		var headers http.Header
+
+		select {
+		case request := <-server.requests:
+			headers = <-server.headers
+
+			expected := lokiPushRequest{}
+			_ = json.Unmarshal([]byte(`{"streams":[
+				{"stream":{"app":"test","component":"db","level":"error"},"values":[["1577934245000000006","msg=\"message 2\" fields.msg=collision"]]},
+				{"stream":{"app":"test","component":"db","level":"info"},"values":[
+					["1577934245000000006","msg=\"message 1\" key=value"],
+					["1577934245000000006","msg=\"message 3\""]
+				]},
+				{"stream":{"app":"test","level":"info"},"values":[["1577934245000000006","msg=\"message 4\""]]}
+			]}`), &expected)
+
+			if want, have := expected, request; !reflect.DeepEqual(want, have) {
+				t.Errorf("unexpected request\nexpected: %+v\nactual:   %+v", want, have)
+			}
+
+		default:
+			t.Fatal("batch should be pushed when flushed")
+		}
+
+		if want, have := "tenant", headers.Get("X-Scope-OrgID"); want != have {
+			t.Errorf("unexpected tenant\nexpected: %q\nactual:   %q", want, have)
+		}
+
+		if want, have := "application/json", headers.Get("Content-Type"); want != have {
+			t.Errorf("unexpected content type\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("BatchSize", func(t *testing.T) {
+		server := newLokiServer(t)
+		defer server.Close()
+
+		config := newLokiTestConfig(server.URL)
+		config.BatchSize = 30
+
+		logger := NewLokiLogger(config)
+		defer logger.Close(context.Background())
+
+		logger.Info("message 1")
+		logger.Info("message 2")
+		logger.Info("message 3")
+
+		request := server.receive(t)
+
+		if want, have := 2, len(request.Streams[0].Values); want != have {
+			t.Errorf("unexpected number of entries\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("BatchWait", func(t *testing.T) {
+		server := newLokiServer(t)
+		defer server.Close()
+
+		config := newLokiTestConfig(server.URL)
+		config.BatchWait = 10 * time.Millisecond
+
+		logger := NewLokiLogger(config)
+		defer logger.Close(context.Background())
+
+		logger.Info("message")
+
+		request := server.receive(t)
+
+		if want, have := 1, len(request.Streams[0].Values); want != have {
+			t.Errorf("unexpected number of entries\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		server := newLokiServer(t)
+		defer server.Close()
+
+		logger := NewLokiLogger(newLokiTestConfig(server.URL))
+
+		logger.Info("message")
+
+		if err := logger.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		server.receive(t)
+
+		if want, have := ErrLokiLoggerClosed, logger.Flush(context.Background()); want != have {
+			t.Errorf("unexpected error\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		logger.Info("message")
+	})
+
+	t.Run("CloseDeadlineBlockedFlush", func(t *testing.T) {
+		started := make(chan struct{}, 10)
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			started <- struct{}{}
+			<-release
+		}))
+		defer server.Close()
+
+		config := newLokiTestConfig(server.URL)
+		config.BatchSize = 1
+		config.QueueSize = 1
+
+		logger := NewLokiLogger(config)
+
+		logger.Info("message 1")
+		<-started
+
+		logger.Info("message 2")
+
+		flushed := make(chan error, 1)
+
+		go func() {
+			flushed <- logger.Flush(context.Background())
+		}()
+
+		// Let the flush block on the full queue
+		time.Sleep(10 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+
+		if err := logger.Close(ctx); err != context.DeadlineExceeded {
+			t.Errorf("close is expected to time out, got: %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("close is expected to respect the deadline, took %s", elapsed)
+		}
+
+		select {
+		case err := <-flushed:
+			if want, have := ErrLokiLoggerClosed, err; want != have {
+				t.Errorf("unexpected error\nexpected: %v\nactual:   %v", want, have)
+			}
+
+		case <-time.After(5 * time.Second):
+			t.Fatal("blocked flush is expected to return after close")
+		}
+
+		close(release)
+
+		if err := logger.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		server := newLokiServer(t, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		logger := NewLokiLogger(newLokiTestConfig(server.URL))
+		defer logger.Close(context.Background())
+
+		logger.Info("message")
+
+		if err := logger.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, have := 3, len(server.requests); want != have {
+			t.Errorf("unexpected number of requests\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("NoRetry", func(t *testing.T) {
+		server := newLokiServer(t, http.StatusBadRequest)
+		defer server.Close()
+
+		logger := NewLokiLogger(newLokiTestConfig(server.URL))
+		defer logger.Close(context.Background())
+
+		logger.Info("message")
+
+		err := logger.Flush(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "400") {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if want, have := 1, len(server.requests); want != have {
+			t.Errorf("unexpected number of requests\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("BlockTimeout", func(t *testing.T) {
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+
+		var (
+			mu   sync.Mutex
+			errs []error
+		)
+
+		config := newLokiTestConfig(server.URL)
+		config.BatchSize = 1
+		config.QueueSize = 1
+		config.BlockTimeout = 10 * time.Millisecond
+		config.ErrorHandler = func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			errs = append(errs, err)
+		}
+
+		logger := NewLokiLogger(config)
+		defer logger.Close(context.Background())
+
+		start := time.Now()
+
+		for i := 0; i < 5; i++ {
+			logger.Info("message")
+		}
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("logging calls should not block longer than the budget, took %s", elapsed)
+		}
+
+		close(release)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if len(errs) == 0 {
+			t.Fatal("events should be dropped")
+		}
+
+		for _, err := range errs {
+			if want, have := ErrLokiQueueFull, err; want != have {
+				t.Errorf("unexpected error\nexpected: %v\nactual:   %v", want, have)
+			}
+		}
+	})
+}
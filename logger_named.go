@@ -0,0 +1,236 @@
+package logur
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LevelSpec assigns levels to named loggers.
+//
+// A spec is a comma separated list of name=level pairs with an optional default level,
+// for example: "info,db=debug,db.pool=trace,http=warn"
+//
+// The level of a named logger is resolved from the most specific matching name:
+// "db" matches "db" and "db.pool", but not "dbx".
+type LevelSpec struct {
+	defaultLevel Level
+	levels       map[string]Level
+}
+
+// ParseLevelSpec parses a level spec string.
+func ParseLevelSpec(spec string) (*LevelSpec, error) {
+	s := &LevelSpec{
+		defaultLevel: Trace,
+		levels:       make(map[string]Level),
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, levelName := "", entry
+
+		if i := strings.IndexByte(entry, '='); i >= 0 {
+			name, levelName = strings.TrimSpace(entry[:i]), strings.TrimSpace(entry[i+1:])
+
+			if name == "" {
+				return nil, fmt.Errorf("missing logger name in level spec entry: %q", entry)
+			}
+		}
+
+		level, ok := ParseLevel(levelName)
+		if !ok {
+			return nil, fmt.Errorf("undefined level in level spec entry: %q", entry)
+		}
+
+		if name == "" {
+			s.defaultLevel = level
+
+			continue
+		}
+
+		s.levels[name] = level
+	}
+
+	return s, nil
+}
+
+// Level resolves the level of a named logger.
+func (s *LevelSpec) Level(name string) Level {
+	if s == nil {
+		return Trace
+	}
+
+	for {
+		if level, ok := s.levels[name]; ok {
+			return level
+		}
+
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return s.defaultLevel
+		}
+
+		name = name[:i]
+	}
+}
+
+// String converts a LevelSpec to its string representation.
+func (s *LevelSpec) String() string {
+	if s == nil {
+		return Trace.String()
+	}
+
+	entries := []string{s.defaultLevel.String()}
+
+	names := make([]string, 0, len(s.levels))
+	for name := range s.levels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		entries = append(entries, name+"="+s.levels[name].String())
+	}
+
+	return strings.Join(entries, ",")
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *LevelSpec) UnmarshalText(text []byte) error {
+	spec, err := ParseLevelSpec(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = *spec
+
+	return nil
+}
+
+// NamedLogger is a logger that is part of a named logger hierarchy.
+//
+// It annotates every log event with a "logger" field containing the dotted name of the logger
+// and drops events below the level resolved for that name from a LevelSpec.
+type NamedLogger struct {
+	base LoggerFacade
+	spec *LevelSpec
+	name string
+
+	logger       LoggerFacade
+	levelEnabler LevelEnabler
+}
+
+// NewNamedLogger returns a new root logger of a named logger hierarchy.
+// Levels of the loggers in the hierarchy are resolved from spec (which can be nil).
+func NewNamedLogger(logger Logger, spec *LevelSpec) *NamedLogger {
+	return newNamedLogger(ensureLoggerFacade(logger), spec, "")
+}
+
+// Named returns a new named logger.
+//
+// If logger is already a NamedLogger, the new name is appended to the name of the logger
+// (and the level spec of the hierarchy is retained).
+func Named(logger Logger, name string) *NamedLogger {
+	if l, ok := logger.(*NamedLogger); ok {
+		return l.Named(name)
+	}
+
+	return newNamedLogger(ensureLoggerFacade(logger), nil, name)
+}
+
+func newNamedLogger(base LoggerFacade, spec *LevelSpec, name string) *NamedLogger {
+	levelLogger := newLevelLogger(base, minLevelEnabler(spec.Level(name)))
+
+	l := &NamedLogger{
+		base:         base,
+		spec:         spec,
+		name:         name,
+		logger:       levelLogger,
+		levelEnabler: levelLogger,
+	}
+
+	if name != "" {
+		l.logger = WithField(levelLogger, "logger", name)
+	}
+
+	return l
+}
+
+// Named returns a new child logger.
+func (l *NamedLogger) Named(name string) *NamedLogger {
+	if name == "" {
+		return l
+	}
+
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	return newNamedLogger(l.base, l.spec, name)
+}
+
+// Name returns the dotted name of the logger.
+func (l *NamedLogger) Name() string {
+	return l.name
+}
+
+// Trace implements the Logger interface.
+func (l *NamedLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.logger.Trace(msg, fields...)
+}
+
+// Debug implements the Logger interface.
+func (l *NamedLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.logger.Debug(msg, fields...)
+}
+
+// Info implements the Logger interface.
+func (l *NamedLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.logger.Info(msg, fields...)
+}
+
+// Warn implements the Logger interface.
+func (l *NamedLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.logger.Warn(msg, fields...)
+}
+
+// Error implements the Logger interface.
+func (l *NamedLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.logger.Error(msg, fields...)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *NamedLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logger.TraceContext(ctx, msg, fields...)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *NamedLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logger.DebugContext(ctx, msg, fields...)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *NamedLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logger.InfoContext(ctx, msg, fields...)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *NamedLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logger.WarnContext(ctx, msg, fields...)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *NamedLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logger.ErrorContext(ctx, msg, fields...)
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *NamedLogger) LevelEnabled(level Level) bool {
+	return l.levelEnabler.LevelEnabled(level)
+}
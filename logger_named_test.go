@@ -0,0 +1,132 @@
+package logur_test
+
+import (
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+	"logur.dev/logur/logtesting"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	spec, err := ParseLevelSpec("info, db=debug,db.pool=trace,http=warn")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]Level{
+		"":             Info,
+		"cache":        Info,
+		"db":           Debug,
+		"dbx":          Info,
+		"db.conn":      Debug,
+		"db.pool":      Trace,
+		"db.pool.idle": Trace,
+		"http":         Warn,
+		"http.client":  Warn,
+	}
+
+	for name, level := range tests {
+		if want, have := level, spec.Level(name); want != have {
+			t.Errorf("unexpected level for %q\nexpected: %s\nactual:   %s", name, want, have)
+		}
+	}
+
+	if want, have := "info,db=debug,db.pool=trace,http=warn", spec.String(); want != have {
+		t.Errorf("unexpected spec string\nexpected: %s\nactual:   %s", want, have)
+	}
+}
+
+func TestParseLevelSpec_Errors(t *testing.T) {
+	for _, spec := range []string{"unknown", "db=unknown", "=debug"} {
+		if _, err := ParseLevelSpec(spec); err == nil {
+			t.Errorf("parsing level spec %q should fail", spec)
+		}
+	}
+}
+
+func TestNamedLogger(t *testing.T) {
+	t.Run("Name", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := Named(testLogger, "db").Named("pool")
+
+		logger.Info("message", map[string]interface{}{"key": "value"})
+
+		logEvent := LogEvent{
+			Line:   "message",
+			Level:  Info,
+			Fields: map[string]interface{}{"logger": "db.pool", "key": "value"},
+		}
+
+		logtesting.AssertLogEventsEqual(t, logEvent, *testLogger.LastEvent())
+
+		if want, have := "db.pool", logger.Name(); want != have {
+			t.Errorf("unexpected name\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("Levels", func(t *testing.T) {
+		spec, _ := ParseLevelSpec("warn,db=debug,db.pool=trace")
+		testLogger := &TestLoggerFacade{}
+
+		root := NewNamedLogger(testLogger, spec)
+
+		root.Info("dropped")
+		root.Named("http").Info("dropped")
+		root.Named("db").Trace("dropped")
+		root.Named("db").Debug("db")
+		Named(root, "db").Named("pool").Trace("db.pool")
+
+		if want, have := 2, testLogger.Count(); want != have {
+			t.Fatalf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		for _, event := range testLogger.Events() {
+			if event.Line != event.Fields["logger"] {
+				t.Errorf("unexpected event: %+v", event)
+			}
+		}
+
+		if root.Named("db").LevelEnabled(Trace) {
+			t.Error("trace level should be disabled for db")
+		}
+
+		if !root.Named("db").Named("pool").LevelEnabled(Trace) {
+			t.Error("trace level should be enabled for db.pool")
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				testLogger := &TestLoggerFacade{}
+				spec, _ := ParseLevelSpec("error,component=" + level.String())
+
+				// The name field is removed from the recorded events, so they match the expected ones
+				return NewNamedLogger(testLogger, spec).Named("component"), conformance.TestLoggerFunc(
+					func() []LogEvent {
+						var events []LogEvent
+
+						for _, event := range testLogger.Events() {
+							fields := make(map[string]interface{}, len(event.Fields))
+
+							for key, value := range event.Fields {
+								if key != "logger" {
+									fields[key] = value
+								}
+							}
+
+							event.Fields = fields
+							events = append(events, event)
+						}
+
+						return events
+					},
+				)
+			},
+		}
+
+		suite.Run(t)
+	})
+}
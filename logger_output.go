@@ -0,0 +1,222 @@
+package logur
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// OutputOption configures the built-in output loggers (eg. NewJSONLogger).
+type OutputOption func(o *outputOptions)
+
+type outputOptions struct {
+	timeKey    string
+	levelKey   string
+	messageKey string
+	timeLayout string
+	minLevel   Level
+	now        func() time.Time
+
+	colorMode    ColorMode
+	relativeTime bool
+}
+
+func newOutputOptions(opts []OutputOption) outputOptions {
+	o := outputOptions{
+		timeKey:    "time",
+		levelKey:   "level",
+		messageKey: "msg",
+		timeLayout: time.RFC3339Nano,
+		minLevel:   Trace,
+		now:        time.Now,
+		colorMode:  ColorAuto,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// TimeKey sets the key of the timestamp in the output.
+// An empty key disables timestamps altogether.
+//
+// A time.Time field under the same key (eg. one added by WithTimestamp) is not written as a field:
+// it is used as the timestamp of the event instead, regardless of where it comes from.
+// Fields of other types colliding with the key are prefixed with "fields.".
+func TimeKey(key string) OutputOption {
+	return func(o *outputOptions) {
+		o.timeKey = key
+	}
+}
+
+// LevelKey sets the key of the level in the output.
+func LevelKey(key string) OutputOption {
+	return func(o *outputOptions) {
+		o.levelKey = key
+	}
+}
+
+// MessageKey sets the key of the message in the output.
+func MessageKey(key string) OutputOption {
+	return func(o *outputOptions) {
+		o.messageKey = key
+	}
+}
+
+// TimeLayout sets the layout used to format timestamps (see time.Time.Format).
+func TimeLayout(layout string) OutputOption {
+	return func(o *outputOptions) {
+		o.timeLayout = layout
+	}
+}
+
+// MinLevel sets the minimum level of events written to the output.
+func MinLevel(level Level) OutputOption {
+	return func(o *outputOptions) {
+		o.minLevel = level
+	}
+}
+
+// outputEvent is a single log event passed to an outputEncoder.
+type outputEvent struct {
+	time   time.Time
+	level  Level
+	msg    string
+	fields map[string]interface{}
+}
+
+// outputEncoder encodes a log event into a single line (including the line break).
+type outputEncoder interface {
+	encode(buf *bytes.Buffer, event outputEvent, options outputOptions)
+}
+
+// nolint: gochecknoglobals
+var outputBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// outputLogger encodes log events and writes them to an io.Writer.
+type outputLogger struct {
+	w       io.Writer
+	encoder outputEncoder
+	options outputOptions
+
+	mu sync.Mutex
+}
+
+func newOutputLogger(w io.Writer, encoder outputEncoder, opts []OutputOption) *outputLogger {
+	return &outputLogger{
+		w:       w,
+		encoder: encoder,
+		options: newOutputOptions(opts),
+	}
+}
+
+// Trace implements the Logger interface.
+func (l *outputLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *outputLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *outputLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *outputLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *outputLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *outputLogger) TraceContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *outputLogger) DebugContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *outputLogger) InfoContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *outputLogger) WarnContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *outputLogger) ErrorContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *outputLogger) LevelEnabled(level Level) bool {
+	return level >= l.options.minLevel
+}
+
+func (l *outputLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	if !l.LevelEnabled(level) {
+		return
+	}
+
+	event := outputEvent{
+		level: level,
+		msg:   msg,
+	}
+
+	if len(fields) > 0 {
+		event.fields = fields[0]
+	}
+
+	if l.options.timeKey != "" {
+		// Prefer the time the event was stamped with (see WithTimestamp)
+		if t, ok := event.fields[l.options.timeKey].(time.Time); ok {
+			event.time = t
+			event.fields = copyFields(event.fields)
+
+			delete(event.fields, l.options.timeKey)
+		} else {
+			event.time = l.options.now()
+		}
+	}
+
+	buf := outputBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	l.encoder.encode(buf, event, l.options)
+
+	l.mu.Lock()
+	_, _ = l.w.Write(buf.Bytes())
+	l.mu.Unlock()
+
+	outputBufferPool.Put(buf)
+}
+
+// outputFieldKey returns the key a field is written under.
+// Fields colliding with one of the reserved keys are prefixed with "fields.".
+func outputFieldKey(key string, options outputOptions) string {
+	if (key == options.timeKey && options.timeKey != "") || key == options.levelKey || key == options.messageKey {
+		return "fields." + key
+	}
+
+	return key
+}
@@ -0,0 +1,239 @@
+package logur
+
+import (
+	"context"
+)
+
+// Record is a log event passed through a chain of processors.
+//
+// Fields might be shared with the caller, so processors should not modify them directly:
+// use SetField and DeleteField or replace Fields with a new map instead.
+type Record struct {
+	Level   Level
+	Message string
+	Fields  map[string]interface{}
+
+	// fieldsOwned is true when Fields is a copy owned by the record
+	fieldsOwned bool
+}
+
+// SetField sets the value of a field without modifying the original fields of the event.
+func (r *Record) SetField(key string, value interface{}) {
+	r.ownFields()
+
+	r.Fields[key] = value
+}
+
+// DeleteField deletes a field without modifying the original fields of the event.
+func (r *Record) DeleteField(key string) {
+	if _, ok := r.Fields[key]; !ok {
+		return
+	}
+
+	r.ownFields()
+
+	delete(r.Fields, key)
+}
+
+func (r *Record) ownFields() {
+	if r.fieldsOwned {
+		return
+	}
+
+	fields := make(map[string]interface{}, len(r.Fields)+1)
+
+	for key, value := range r.Fields {
+		fields[key] = value
+	}
+
+	r.Fields = fields
+	r.fieldsOwned = true
+}
+
+// Processor processes a log event before it is passed to a logger.
+// It may modify the record (eg. enrich, rename or redact fields) or drop the event by returning false.
+//
+// The context is the one passed to *Context methods or context.Background() for the rest.
+type Processor func(ctx context.Context, record *Record) (keep bool)
+
+// WithProcessors returns a new logger that passes every log event through a chain of processors.
+//
+// Processors are only called for enabled levels (if the underlying logger implements LevelEnabler).
+// The returned logger also implements LevelEnabler.
+func WithProcessors(logger Logger, processors ...Processor) LoggerFacade {
+	// Do not add a new layer, append the processors to the existing chain instead
+	if l, ok := logger.(*processorLogger); ok {
+		chain := make([]Processor, 0, len(l.processors)+len(processors))
+		chain = append(chain, l.processors...)
+		chain = append(chain, processors...)
+
+		return &processorLogger{
+			logger:       l.logger,
+			levelEnabler: l.levelEnabler,
+			processors:   chain,
+		}
+	}
+
+	l := &processorLogger{
+		logger:     ensureLoggerFacade(logger),
+		processors: processors,
+	}
+
+	if levelEnabler, ok := logger.(LevelEnabler); ok {
+		l.levelEnabler = levelEnabler
+	}
+
+	return l
+}
+
+// processorLogger passes log events through a chain of processors.
+type processorLogger struct {
+	logger       LoggerFacade
+	levelEnabler LevelEnabler
+	processors   []Processor
+}
+
+// Trace implements the Logger interface.
+func (l *processorLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.process(nil, Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *processorLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.process(nil, Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *processorLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.process(nil, Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *processorLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.process(nil, Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *processorLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.process(nil, Error, msg, fields)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *processorLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.process(ctx, Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *processorLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.process(ctx, Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *processorLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.process(ctx, Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *processorLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.process(ctx, Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *processorLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.process(ctx, Error, msg, fields)
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *processorLogger) LevelEnabled(level Level) bool {
+	if l.levelEnabler != nil {
+		return l.levelEnabler.LevelEnabled(level)
+	}
+
+	return true
+}
+
+// process runs the processor chain and passes the event to the underlying logger.
+// A nil context means the event was logged without a context.
+func (l *processorLogger) process(ctx context.Context, level Level, msg string, fields []map[string]interface{}) {
+	if !l.LevelEnabled(level) {
+		return
+	}
+
+	record := Record{
+		Level:   level,
+		Message: msg,
+	}
+
+	if len(fields) > 0 {
+		record.Fields = fields[0]
+	}
+
+	processorCtx := ctx
+	if processorCtx == nil {
+		processorCtx = context.Background()
+	}
+
+	for _, processor := range l.processors {
+		if !processor(processorCtx, &record) {
+			return
+		}
+	}
+
+	if record.Fields != nil {
+		fields = []map[string]interface{}{record.Fields}
+	} else {
+		fields = nil
+	}
+
+	if ctx != nil {
+		LevelContextFunc(l.logger, record.Level)(ctx, record.Message, fields...)
+
+		return
+	}
+
+	LevelFunc(l.logger, record.Level)(record.Message, fields...)
+}
+
+// FieldsProcessor returns a Processor that adds fields to every log event.
+// Fields of the event take precedence over the added ones.
+func FieldsProcessor(fields map[string]interface{}) Processor {
+	return func(_ context.Context, record *Record) bool {
+		for key, value := range fields {
+			if _, ok := record.Fields[key]; !ok {
+				record.SetField(key, value)
+			}
+		}
+
+		return true
+	}
+}
+
+// ContextExtractorProcessor returns a Processor that adds fields extracted from the context to every log event.
+// Fields of the event take precedence over the extracted ones.
+func ContextExtractorProcessor(extractor ContextExtractor) Processor {
+	return func(ctx context.Context, record *Record) bool {
+		return FieldsProcessor(extractor(ctx))(ctx, record)
+	}
+}
+
+// RenameFieldProcessor returns a Processor that renames a field.
+func RenameFieldProcessor(from string, to string) Processor {
+	return func(_ context.Context, record *Record) bool {
+		value, ok := record.Fields[from]
+		if !ok {
+			return true
+		}
+
+		record.DeleteField(from)
+		record.SetField(to, value)
+
+		return true
+	}
+}
+
+// LevelFilterProcessor returns a Processor that drops every event below a level.
+func LevelFilterProcessor(level Level) Processor {
+	return func(_ context.Context, record *Record) bool {
+		return record.Level >= level
+	}
+}
@@ -0,0 +1,135 @@
+package logur_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+	"logur.dev/logur/logtesting"
+)
+
+func TestWithProcessors(t *testing.T) {
+	t.Run("Chain", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		fields := map[string]interface{}{"user": "john", "password": "secret", "drop": false}
+
+		logger := WithProcessors(
+			WithProcessors(
+				testLogger,
+				FieldsProcessor(map[string]interface{}{"app": "example", "user": "overridden"}),
+				func(_ context.Context, record *Record) bool {
+					return record.Fields["drop"] != true
+				},
+			),
+			RenameFieldProcessor("user", "username"),
+			RedactionProcessor(RedactionPolicy{Keys: []string{"password"}}),
+		)
+
+		logger.Info("message", fields)
+		logger.Info("dropped", map[string]interface{}{"drop": true})
+
+		if want, have := 1, testLogger.Count(); want != have {
+			t.Fatalf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		logEvent := LogEvent{
+			Line:  "message",
+			Level: Info,
+			Fields: map[string]interface{}{
+				"app":      "example",
+				"username": "john",
+				"password": "[REDACTED]",
+				"drop":     false,
+			},
+		}
+
+		logtesting.AssertLogEventsEqual(t, logEvent, *testLogger.LastEvent())
+
+		expectedFields := map[string]interface{}{"user": "john", "password": "secret", "drop": false}
+
+		if !reflect.DeepEqual(expectedFields, fields) {
+			t.Errorf("original fields should not be modified: %v", fields)
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithProcessors(
+			testLogger,
+			ContextExtractorProcessor(func(ctx context.Context) map[string]interface{} {
+				return map[string]interface{}{"key": ctx.Value(contextKey("key"))}
+			}),
+			func(_ context.Context, record *Record) bool {
+				record.Level = Warn
+				record.Message = "changed"
+
+				return true
+			},
+		)
+
+		logger.InfoContext(context.WithValue(context.Background(), contextKey("key"), "value"), "message")
+
+		logEvent := LogEvent{
+			Line:   "changed",
+			Level:  Warn,
+			Fields: map[string]interface{}{"key": "value"},
+		}
+
+		logtesting.AssertLogEventsEqual(t, logEvent, *testLogger.LastEvent())
+	})
+
+	t.Run("LevelFilter", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithProcessors(testLogger, LevelFilterProcessor(Warn))
+
+		logger.Info("message")
+		logger.Warn("message")
+
+		if want, have := 1, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("DisabledLevelAllocations", func(t *testing.T) {
+		called := false
+
+		logger := WithProcessors(
+			WithMinLevel(NoopLogger{}, Error),
+			func(_ context.Context, record *Record) bool {
+				called = true
+
+				return true
+			},
+		)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			logger.Debug("message")
+			logger.InfoContext(context.Background(), "message")
+		})
+
+		if allocs != 0 {
+			t.Errorf("disabled levels should not allocate, got %v allocations", allocs)
+		}
+
+		if called {
+			t.Error("processors should not be called for disabled levels")
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				logger := &TestLoggerFacade{}
+
+				return WithProcessors(WithMinLevel(logger, level), FieldsProcessor(nil)), logger
+			},
+		}
+
+		suite.Run(t)
+	})
+}
@@ -0,0 +1,267 @@
+package logur
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimitKeyFunc returns the key a log event is rate limited by.
+type RateLimitKeyFunc func(level Level, msg string, fields map[string]interface{}) string
+
+// RateLimitByMessage rate limits log events by their message.
+func RateLimitByMessage(_ Level, msg string, _ map[string]interface{}) string {
+	return msg
+}
+
+// RateLimitGlobal rate limits all log events together.
+func RateLimitGlobal(_ Level, _ string, _ map[string]interface{}) string {
+	return ""
+}
+
+// RateLimitByField rate limits log events by the value of a field (eg. tenant_id).
+// Events without the field share the same key.
+func RateLimitByField(key string) RateLimitKeyFunc {
+	return func(_ Level, _ string, fields map[string]interface{}) string {
+		value, ok := fields[key]
+		if !ok {
+			return ""
+		}
+
+		return fmt.Sprint(value)
+	}
+}
+
+// RateLimiterConfig configures a rate limited logger.
+type RateLimiterConfig struct {
+	// Rate is the number of events allowed per second for each key (defaults to 10).
+	Rate float64
+
+	// Burst is the maximum number of events allowed at once for each key (defaults to Rate, but at least one).
+	Burst int
+
+	// KeyFunc returns the key of an event (defaults to RateLimitByMessage).
+	KeyFunc RateLimitKeyFunc
+
+	// MaxKeys is the maximum number of keys tracked at once (defaults to 1024).
+	// The least recently used keys are evicted first.
+	MaxKeys int
+}
+
+// WithRateLimiter returns a new logger that limits the rate of log events using a token bucket per key.
+//
+// The number of events suppressed since the last event with the same key
+// is added to the next logged event as a "suppressed" field.
+//
+// The returned logger also implements LevelEnabler.
+func WithRateLimiter(logger Logger, config RateLimiterConfig) LoggerFacade {
+	if config.Rate <= 0 {
+		config.Rate = 10
+	}
+
+	if config.Burst <= 0 {
+		config.Burst = int(config.Rate)
+
+		if config.Burst < 1 {
+			config.Burst = 1
+		}
+	}
+
+	if config.KeyFunc == nil {
+		config.KeyFunc = RateLimitByMessage
+	}
+
+	if config.MaxKeys <= 0 {
+		config.MaxKeys = 1024
+	}
+
+	l := &rateLimitLogger{
+		logger:  ensureLoggerFacade(logger),
+		config:  config,
+		now:     time.Now,
+		buckets: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+
+	if levelEnabler, ok := logger.(LevelEnabler); ok {
+		l.levelEnabler = levelEnabler
+	}
+
+	return l
+}
+
+type rateLimitBucket struct {
+	key        string
+	tokens     float64
+	last       time.Time
+	suppressed int
+}
+
+// rateLimitLogger drops log events exceeding the rate limit of their key.
+type rateLimitLogger struct {
+	logger       LoggerFacade
+	levelEnabler LevelEnabler
+	config       RateLimiterConfig
+	now          func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	lru     *list.List
+}
+
+// Trace implements the Logger interface.
+func (l *rateLimitLogger) Trace(msg string, fields ...map[string]interface{}) {
+	if fields, ok := l.allow(Trace, msg, fields); ok {
+		l.logger.Trace(msg, fields...)
+	}
+}
+
+// Debug implements the Logger interface.
+func (l *rateLimitLogger) Debug(msg string, fields ...map[string]interface{}) {
+	if fields, ok := l.allow(Debug, msg, fields); ok {
+		l.logger.Debug(msg, fields...)
+	}
+}
+
+// Info implements the Logger interface.
+func (l *rateLimitLogger) Info(msg string, fields ...map[string]interface{}) {
+	if fields, ok := l.allow(Info, msg, fields); ok {
+		l.logger.Info(msg, fields...)
+	}
+}
+
+// Warn implements the Logger interface.
+func (l *rateLimitLogger) Warn(msg string, fields ...map[string]interface{}) {
+	if fields, ok := l.allow(Warn, msg, fields); ok {
+		l.logger.Warn(msg, fields...)
+	}
+}
+
+// Error implements the Logger interface.
+func (l *rateLimitLogger) Error(msg string, fields ...map[string]interface{}) {
+	if fields, ok := l.allow(Error, msg, fields); ok {
+		l.logger.Error(msg, fields...)
+	}
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *rateLimitLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if fields, ok := l.allow(Trace, msg, fields); ok {
+		l.logger.TraceContext(ctx, msg, fields...)
+	}
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *rateLimitLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if fields, ok := l.allow(Debug, msg, fields); ok {
+		l.logger.DebugContext(ctx, msg, fields...)
+	}
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *rateLimitLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if fields, ok := l.allow(Info, msg, fields); ok {
+		l.logger.InfoContext(ctx, msg, fields...)
+	}
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *rateLimitLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if fields, ok := l.allow(Warn, msg, fields); ok {
+		l.logger.WarnContext(ctx, msg, fields...)
+	}
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *rateLimitLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if fields, ok := l.allow(Error, msg, fields); ok {
+		l.logger.ErrorContext(ctx, msg, fields...)
+	}
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *rateLimitLogger) LevelEnabled(level Level) bool {
+	if l.levelEnabler != nil {
+		return l.levelEnabler.LevelEnabled(level)
+	}
+
+	return true
+}
+
+// allow takes a token from the bucket of an event.
+// If the event is allowed, the returned fields contain the number of previously suppressed events (if any).
+func (l *rateLimitLogger) allow(
+	level Level,
+	msg string,
+	fields []map[string]interface{},
+) ([]map[string]interface{}, bool) {
+	if !l.LevelEnabled(level) {
+		return nil, false
+	}
+
+	var f map[string]interface{}
+	if len(fields) > 0 {
+		f = fields[0]
+	}
+
+	key := l.config.KeyFunc(level, msg, f)
+	now := l.now()
+
+	l.mu.Lock()
+
+	bucket := l.bucket(key, now)
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * l.config.Rate
+	if burst := float64(l.config.Burst); bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		bucket.suppressed++
+		l.mu.Unlock()
+
+		return nil, false
+	}
+
+	bucket.tokens--
+
+	suppressed := bucket.suppressed
+	bucket.suppressed = 0
+
+	l.mu.Unlock()
+
+	if suppressed > 0 {
+		fields = []map[string]interface{}{mergeFields(Fields{"suppressed": suppressed}, fields)}
+	}
+
+	return fields, true
+}
+
+// bucket returns the bucket for a key (creating it if necessary) and marks it as recently used.
+func (l *rateLimitLogger) bucket(key string, now time.Time) *rateLimitBucket {
+	if elem, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(elem)
+
+		return elem.Value.(*rateLimitBucket)
+	}
+
+	if l.lru.Len() >= l.config.MaxKeys {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.buckets, oldest.Value.(*rateLimitBucket).key)
+	}
+
+	bucket := &rateLimitBucket{
+		key:    key,
+		tokens: float64(l.config.Burst),
+		last:   now,
+	}
+
+	l.buckets[key] = l.lru.PushFront(bucket)
+
+	return bucket
+}
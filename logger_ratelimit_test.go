@@ -0,0 +1,113 @@
+package logur_test
+
+import (
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+	"logur.dev/logur/logtesting"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("Suppressed", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithRateLimiter(testLogger, RateLimiterConfig{Rate: 50, Burst: 1})
+
+		logger.Info("message")
+		logger.Info("message")
+		logger.Info("message")
+		logger.Info("other message")
+
+		if want, have := 2, testLogger.Count(); want != have {
+			t.Fatalf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+		logger.Info("message", map[string]interface{}{"key": "value"})
+
+		logEvent := LogEvent{
+			Line:   "message",
+			Level:  Info,
+			Fields: map[string]interface{}{"key": "value", "suppressed": 2},
+		}
+
+		logtesting.AssertLogEventsEqual(t, logEvent, *testLogger.LastEvent())
+	})
+
+	t.Run("DefaultRate", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithRateLimiter(testLogger, RateLimiterConfig{})
+
+		for i := 0; i < 20; i++ {
+			logger.Info("message")
+		}
+
+		if want, have := 10, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("ByField", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithRateLimiter(testLogger, RateLimiterConfig{
+			Rate:    0.001,
+			Burst:   2,
+			KeyFunc: RateLimitByField("tenant_id"),
+		})
+
+		for i := 0; i < 5; i++ {
+			logger.Info("message", map[string]interface{}{"tenant_id": 1})
+			logger.Warn("other message", map[string]interface{}{"tenant_id": 2})
+		}
+
+		if want, have := 4, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("Global", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithRateLimiter(testLogger, RateLimiterConfig{Rate: 0.001, KeyFunc: RateLimitGlobal})
+
+		logger.Info("message")
+		logger.Info("other message")
+		logger.Error("error")
+
+		if want, have := 1, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("MaxKeys", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithRateLimiter(testLogger, RateLimiterConfig{Rate: 0.001, MaxKeys: 1})
+
+		// Evicting the bucket of a key resets its limit
+		logger.Info("message")
+		logger.Info("other message")
+		logger.Info("message")
+
+		if want, have := 3, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				logger := &TestLoggerFacade{}
+
+				return WithRateLimiter(WithMinLevel(logger, level), RateLimiterConfig{Rate: 1}), logger
+			},
+		}
+
+		suite.Run(t)
+	})
+}
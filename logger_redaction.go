@@ -0,0 +1,350 @@
+package logur
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// RedactionPolicy describes which fields should be redacted and how.
+type RedactionPolicy struct {
+	// Keys is a list of field keys to redact.
+	// A key may be a glob pattern: "*" matches any sequence of characters, "?" matches a single character
+	// (eg. "*_token").
+	Keys []string
+
+	// CaseInsensitive makes key matching case-insensitive.
+	CaseInsensitive bool
+
+	// Mask replaces redacted values (defaults to "[REDACTED]").
+	Mask string
+
+	// HashSalt makes redacted values replaced by a salted hash of the original value (instead of Mask),
+	// so that values can still be correlated without being revealed.
+	HashSalt []byte
+}
+
+// WithRedaction returns a new logger that redacts field values based on their keys.
+//
+// Fields are searched recursively through nested maps (and slices).
+// String slices under redacted keys are redacted element by element.
+// The original fields are never modified.
+//
+// The returned logger also implements LevelEnabler.
+func WithRedaction(logger Logger, policy RedactionPolicy) LoggerFacade {
+	l := &redactionLogger{
+		logger:   ensureLoggerFacade(logger),
+		redactor: newRedactor(policy),
+	}
+
+	if levelEnabler, ok := logger.(LevelEnabler); ok {
+		l.levelEnabler = levelEnabler
+	}
+
+	return l
+}
+
+// RedactionProcessor returns a Processor that redacts field values based on their keys.
+// See WithRedaction for details.
+func RedactionProcessor(policy RedactionPolicy) Processor {
+	r := newRedactor(policy)
+
+	return func(_ context.Context, record *Record) bool {
+		if len(record.Fields) > 0 {
+			record.Fields = r.redactMap(record.Fields)
+		}
+
+		return true
+	}
+}
+
+// redactionLogger redacts field values before passing them to the underlying logger.
+type redactionLogger struct {
+	logger       LoggerFacade
+	levelEnabler LevelEnabler
+	redactor     *redactor
+}
+
+// Trace implements the Logger interface.
+func (l *redactionLogger) Trace(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Trace) {
+		return
+	}
+
+	l.logger.Trace(msg, l.redactFields(fields)...)
+}
+
+// Debug implements the Logger interface.
+func (l *redactionLogger) Debug(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Debug) {
+		return
+	}
+
+	l.logger.Debug(msg, l.redactFields(fields)...)
+}
+
+// Info implements the Logger interface.
+func (l *redactionLogger) Info(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Info) {
+		return
+	}
+
+	l.logger.Info(msg, l.redactFields(fields)...)
+}
+
+// Warn implements the Logger interface.
+func (l *redactionLogger) Warn(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Warn) {
+		return
+	}
+
+	l.logger.Warn(msg, l.redactFields(fields)...)
+}
+
+// Error implements the Logger interface.
+func (l *redactionLogger) Error(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Error) {
+		return
+	}
+
+	l.logger.Error(msg, l.redactFields(fields)...)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *redactionLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Trace) {
+		return
+	}
+
+	l.logger.TraceContext(ctx, msg, l.redactFields(fields)...)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *redactionLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Debug) {
+		return
+	}
+
+	l.logger.DebugContext(ctx, msg, l.redactFields(fields)...)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *redactionLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Info) {
+		return
+	}
+
+	l.logger.InfoContext(ctx, msg, l.redactFields(fields)...)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *redactionLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Warn) {
+		return
+	}
+
+	l.logger.WarnContext(ctx, msg, l.redactFields(fields)...)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *redactionLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Error) {
+		return
+	}
+
+	l.logger.ErrorContext(ctx, msg, l.redactFields(fields)...)
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *redactionLogger) LevelEnabled(level Level) bool {
+	if l.levelEnabler != nil {
+		return l.levelEnabler.LevelEnabled(level)
+	}
+
+	return true
+}
+
+func (l *redactionLogger) redactFields(fields []map[string]interface{}) []map[string]interface{} {
+	if len(fields) == 0 || len(fields[0]) == 0 {
+		return fields
+	}
+
+	return []map[string]interface{}{l.redactor.redactMap(fields[0])}
+}
+
+// redactor redacts values in field maps based on a RedactionPolicy.
+type redactor struct {
+	policy RedactionPolicy
+
+	keys     map[string]bool
+	patterns []string
+}
+
+func newRedactor(policy RedactionPolicy) *redactor {
+	if policy.Mask == "" {
+		policy.Mask = "[REDACTED]"
+	}
+
+	r := &redactor{
+		policy: policy,
+		keys:   make(map[string]bool),
+	}
+
+	for _, key := range policy.Keys {
+		if policy.CaseInsensitive {
+			key = strings.ToLower(key)
+		}
+
+		if strings.ContainsAny(key, "*?") {
+			r.patterns = append(r.patterns, key)
+		} else {
+			r.keys[key] = true
+		}
+	}
+
+	return r
+}
+
+func (r *redactor) redactMap(m map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(m))
+
+	for key, value := range m {
+		if r.match(key) {
+			redacted[key] = r.redactValue(value)
+
+			continue
+		}
+
+		redacted[key] = r.redactNested(value)
+	}
+
+	return redacted
+}
+
+// redactNested looks for fields to redact in nested values.
+func (r *redactor) redactNested(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return r.redactMap(v)
+
+	case Fields:
+		return Fields(r.redactMap(v))
+
+	case map[string]string:
+		s := make(map[string]string, len(v))
+
+		for key, item := range v {
+			if r.match(key) {
+				s[key] = r.mask(item)
+
+				continue
+			}
+
+			s[key] = item
+		}
+
+		return s
+
+	case []interface{}:
+		s := make([]interface{}, len(v))
+
+		for i, item := range v {
+			s[i] = r.redactNested(item)
+		}
+
+		return s
+
+	case []map[string]interface{}:
+		s := make([]map[string]interface{}, len(v))
+
+		for i, item := range v {
+			s[i] = r.redactMap(item)
+		}
+
+		return s
+
+	default:
+		return value
+	}
+}
+
+func (r *redactor) redactValue(value interface{}) interface{} {
+	if v, ok := value.([]string); ok {
+		s := make([]string, len(v))
+
+		for i, item := range v {
+			s[i] = r.mask(item)
+		}
+
+		return s
+	}
+
+	return r.mask(value)
+}
+
+// mask returns the mask or the salted hash of a value.
+func (r *redactor) mask(value interface{}) string {
+	if r.policy.HashSalt == nil {
+		return r.policy.Mask
+	}
+
+	mac := hmac.New(sha256.New, r.policy.HashSalt)
+	_, _ = fmt.Fprint(mac, value)
+
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (r *redactor) match(key string) bool {
+	if r.policy.CaseInsensitive {
+		key = strings.ToLower(key)
+	}
+
+	if r.keys[key] {
+		return true
+	}
+
+	for _, pattern := range r.patterns {
+		if globMatch(pattern, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// globMatch matches a string against a simple glob pattern.
+// "*" matches any sequence of characters, "?" matches a single character.
+func globMatch(pattern string, s string) bool {
+	// Position to backtrack to when a "*" is found
+	starPattern, starS := -1, -1
+
+	p, i := 0, 0
+
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[i]):
+			p++
+			i++
+
+		case p < len(pattern) && pattern[p] == '*':
+			starPattern, starS = p, i
+			p++
+
+		case starPattern >= 0:
+			starS++
+			p, i = starPattern+1, starS
+
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
@@ -0,0 +1,150 @@
+package logur_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+func TestRedaction(t *testing.T) {
+	t.Run("Keys", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithRedaction(testLogger, RedactionPolicy{
+			Keys:            []string{"password", "*_token", "secret?"},
+			CaseInsensitive: true,
+		})
+
+		fields := map[string]interface{}{
+			"Password":      "hunter2",
+			"access_token":  "abc",
+			"token":         "visible",
+			"secret1":       "s",
+			"secret12":      "visible",
+			"user":          "john",
+			"nested":        map[string]interface{}{"PASSWORD": "hunter2", "ok": 1},
+			"nested_fields": Fields{"refresh_token": "abc"},
+			"list":          []interface{}{map[string]interface{}{"password": "hunter2"}, "item"},
+		}
+
+		logger.Info("message", fields)
+
+		expected := map[string]interface{}{
+			"Password":      "[REDACTED]",
+			"access_token":  "[REDACTED]",
+			"token":         "visible",
+			"secret1":       "[REDACTED]",
+			"secret12":      "visible",
+			"user":          "john",
+			"nested":        map[string]interface{}{"PASSWORD": "[REDACTED]", "ok": 1},
+			"nested_fields": Fields{"refresh_token": "[REDACTED]"},
+			"list":          []interface{}{map[string]interface{}{"password": "[REDACTED]"}, "item"},
+		}
+
+		if want, have := expected, testLogger.LastEvent().Fields; !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected fields\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := "hunter2", fields["nested"].(map[string]interface{})["PASSWORD"]; want != have {
+			t.Error("original fields should not be modified")
+		}
+	})
+
+	t.Run("StringCollections", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithRedaction(testLogger, RedactionPolicy{Keys: []string{"password", "tokens"}})
+
+		fields := map[string]interface{}{
+			"headers": map[string]string{"password": "hunter2", "user": "john"},
+			"tokens":  []string{"abc", "def"},
+			"names":   []string{"john"},
+		}
+
+		logger.Info("message", fields)
+
+		expected := map[string]interface{}{
+			"headers": map[string]string{"password": "[REDACTED]", "user": "john"},
+			"tokens":  []string{"[REDACTED]", "[REDACTED]"},
+			"names":   []string{"john"},
+		}
+
+		if want, have := expected, testLogger.LastEvent().Fields; !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected fields\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := "hunter2", fields["headers"].(map[string]string)["password"]; want != have {
+			t.Error("original fields should not be modified")
+		}
+	})
+
+	t.Run("CaseSensitive", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithRedaction(testLogger, RedactionPolicy{Keys: []string{"password"}, Mask: "***"})
+
+		logger.Info("message", map[string]interface{}{"password": "hunter2", "Password": "hunter2"})
+
+		expected := map[string]interface{}{"password": "***", "Password": "hunter2"}
+
+		if want, have := expected, testLogger.LastEvent().Fields; !reflect.DeepEqual(want, have) {
+			t.Errorf("unexpected fields\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("DisabledLevel", func(t *testing.T) {
+		logger := WithRedaction(WithMinLevel(&TestLoggerFacade{}, Info), RedactionPolicy{Keys: []string{"password"}})
+
+		fields := map[string]interface{}{"password": "hunter2"}
+
+		allocs := testing.AllocsPerRun(10, func() {
+			logger.Debug("message", fields)
+		})
+
+		// The variadic fields slice escapes at the call site
+		if allocs > 1 {
+			t.Errorf("disabled levels should not be redacted, got %v allocations", allocs)
+		}
+	})
+
+	t.Run("Hash", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithRedaction(testLogger, RedactionPolicy{Keys: []string{"email"}, HashSalt: []byte("salt")})
+
+		logger.Info("message", map[string]interface{}{"email": "john@example.com"})
+		logger.Info("message", map[string]interface{}{"email": "john@example.com"})
+		logger.Info("message", map[string]interface{}{"email": "jane@example.com"})
+
+		events := testLogger.Events()
+
+		hash := events[0].Fields["email"].(string)
+
+		if !strings.HasPrefix(hash, "sha256:") || strings.Contains(hash, "john") {
+			t.Errorf("unexpected hash: %s", hash)
+		}
+
+		if events[1].Fields["email"] != hash {
+			t.Error("hashes of the same value should be equal")
+		}
+
+		if events[2].Fields["email"] == hash {
+			t.Error("hashes of different values should not be equal")
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				logger := &TestLoggerFacade{}
+
+				return WithRedaction(WithMinLevel(logger, level), RedactionPolicy{Keys: []string{"password"}}), logger
+			},
+		}
+
+		suite.Run(t)
+	})
+}
@@ -0,0 +1,255 @@
+package logur
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SamplingRule describes how log events with the same level and message are sampled in a single tick.
+type SamplingRule struct {
+	// First is the number of events logged in each tick.
+	First int
+
+	// Thereafter makes the sampler log every Mth event after the first ones.
+	// Zero drops every event after the first ones.
+	Thereafter int
+}
+
+// SamplerConfig configures a sampling logger.
+type SamplerConfig struct {
+	// Tick is the period sampling counters are reset after (defaults to one second).
+	Tick time.Duration
+
+	// SamplingRule is the default rule applied to every level
+	// (defaults to logging the first 100 events, then every 100th event).
+	SamplingRule
+
+	// Levels overrides the default rule for specific levels.
+	// Error events are never sampled.
+	Levels map[Level]SamplingRule
+
+	// SummaryInterval enables summary events (on Warn level) about the number of dropped events.
+	// A summary is logged one interval after the first event dropped since the previous summary.
+	SummaryInterval time.Duration
+}
+
+// WithSampler returns a new logger that samples log events to reduce the log volume.
+//
+// For every level and message it logs the first N events in each tick, then every Mth event after that.
+// Error events are never dropped.
+func WithSampler(logger Logger, config SamplerConfig) *SamplerLogger {
+	if config.Tick <= 0 {
+		config.Tick = time.Second
+	}
+
+	if config.SamplingRule == (SamplingRule{}) {
+		config.SamplingRule = SamplingRule{First: 100, Thereafter: 100}
+	}
+
+	l := &SamplerLogger{
+		logger:   ensureLoggerFacade(logger),
+		config:   config,
+		now:      time.Now,
+		counters: make(map[samplerKey]int),
+	}
+
+	if levelEnabler, ok := logger.(LevelEnabler); ok {
+		l.levelEnabler = levelEnabler
+	}
+
+	return l
+}
+
+type samplerKey struct {
+	level Level
+	msg   string
+}
+
+// SamplerLogger drops log events based on SamplingRules.
+//
+// Call Flush to log the summary of dropped events immediately (eg. before exiting).
+type SamplerLogger struct {
+	logger       LoggerFacade
+	levelEnabler LevelEnabler
+	config       SamplerConfig
+	now          func() time.Time
+
+	mu        sync.Mutex
+	tickStart time.Time
+	counters  map[samplerKey]int
+	dropped   int
+	timer     *time.Timer
+}
+
+// Trace implements the Logger interface.
+func (l *SamplerLogger) Trace(msg string, fields ...map[string]interface{}) {
+	if !l.sample(Trace, msg) {
+		return
+	}
+
+	l.logger.Trace(msg, fields...)
+}
+
+// Debug implements the Logger interface.
+func (l *SamplerLogger) Debug(msg string, fields ...map[string]interface{}) {
+	if !l.sample(Debug, msg) {
+		return
+	}
+
+	l.logger.Debug(msg, fields...)
+}
+
+// Info implements the Logger interface.
+func (l *SamplerLogger) Info(msg string, fields ...map[string]interface{}) {
+	if !l.sample(Info, msg) {
+		return
+	}
+
+	l.logger.Info(msg, fields...)
+}
+
+// Warn implements the Logger interface.
+func (l *SamplerLogger) Warn(msg string, fields ...map[string]interface{}) {
+	if !l.sample(Warn, msg) {
+		return
+	}
+
+	l.logger.Warn(msg, fields...)
+}
+
+// Error implements the Logger interface.
+func (l *SamplerLogger) Error(msg string, fields ...map[string]interface{}) {
+	if !l.sample(Error, msg) {
+		return
+	}
+
+	l.logger.Error(msg, fields...)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *SamplerLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.sample(Trace, msg) {
+		return
+	}
+
+	l.logger.TraceContext(ctx, msg, fields...)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *SamplerLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.sample(Debug, msg) {
+		return
+	}
+
+	l.logger.DebugContext(ctx, msg, fields...)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *SamplerLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.sample(Info, msg) {
+		return
+	}
+
+	l.logger.InfoContext(ctx, msg, fields...)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *SamplerLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.sample(Warn, msg) {
+		return
+	}
+
+	l.logger.WarnContext(ctx, msg, fields...)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *SamplerLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.sample(Error, msg) {
+		return
+	}
+
+	l.logger.ErrorContext(ctx, msg, fields...)
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *SamplerLogger) LevelEnabled(level Level) bool {
+	if l.levelEnabler != nil {
+		return l.levelEnabler.LevelEnabled(level)
+	}
+
+	return true
+}
+
+// Flush logs a summary of the events dropped since the previous summary (if any)
+// and stops the pending summary timer.
+func (l *SamplerLogger) Flush() {
+	l.mu.Lock()
+
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+
+	dropped := l.dropped
+	l.dropped = 0
+
+	l.mu.Unlock()
+
+	if dropped > 0 && l.LevelEnabled(Warn) {
+		l.logger.Warn("log events dropped by sampler", map[string]interface{}{"dropped": dropped})
+	}
+}
+
+// sample decides whether an event should be logged.
+// It also schedules a summary of dropped events if necessary.
+func (l *SamplerLogger) sample(level Level, msg string) bool {
+	if !l.LevelEnabled(level) {
+		return false
+	}
+
+	now := l.now()
+
+	l.mu.Lock()
+
+	if now.Sub(l.tickStart) >= l.config.Tick {
+		l.tickStart = now
+		l.counters = make(map[samplerKey]int, len(l.counters))
+	}
+
+	keep := l.keep(level, msg)
+	if !keep {
+		l.dropped++
+
+		if l.config.SummaryInterval > 0 && l.timer == nil {
+			l.timer = time.AfterFunc(l.config.SummaryInterval, l.Flush)
+		}
+	}
+
+	l.mu.Unlock()
+
+	return keep
+}
+
+// keep increments the counter of an event and checks it against the sampling rule of the level.
+func (l *SamplerLogger) keep(level Level, msg string) bool {
+	if level >= Error {
+		return true
+	}
+
+	rule, ok := l.config.Levels[level]
+	if !ok {
+		rule = l.config.SamplingRule
+	}
+
+	key := samplerKey{level: level, msg: msg}
+
+	l.counters[key]++
+	n := l.counters[key]
+
+	if n <= rule.First {
+		return true
+	}
+
+	return rule.Thereafter > 0 && (n-rule.First)%rule.Thereafter == 0
+}
@@ -0,0 +1,198 @@
+package logur_test
+
+import (
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+	"logur.dev/logur/logtesting"
+)
+
+// levelEnablerFunc is a LevelEnabler backed by a function.
+type levelEnablerFunc func(level Level) bool
+
+func (fn levelEnablerFunc) LevelEnabled(level Level) bool {
+	return fn(level)
+}
+
+func TestSampler(t *testing.T) {
+	t.Run("Sampling", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithSampler(testLogger, SamplerConfig{
+			Tick:         time.Hour,
+			SamplingRule: SamplingRule{First: 2, Thereafter: 3},
+		})
+
+		for i := 0; i < 10; i++ {
+			logger.Info("message")
+			logger.Info("other message")
+		}
+
+		// 1, 2, 5, 8 for both messages
+		if want, have := 8, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("DefaultRule", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithSampler(testLogger, SamplerConfig{Tick: time.Hour})
+
+		for i := 0; i < 300; i++ {
+			logger.Info("message")
+		}
+
+		// 1-100, 200, 300
+		if want, have := 102, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("Levels", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithSampler(testLogger, SamplerConfig{
+			Tick:         time.Hour,
+			SamplingRule: SamplingRule{First: 1},
+			Levels: map[Level]SamplingRule{
+				Warn:  {First: 5},
+				Error: {First: 1},
+			},
+		})
+
+		for i := 0; i < 10; i++ {
+			logger.Debug("message")
+			logger.Warn("message")
+			logger.Error("message")
+		}
+
+		counts := make(map[Level]int)
+		for _, event := range testLogger.Events() {
+			counts[event.Level]++
+		}
+
+		if counts[Debug] != 1 || counts[Warn] != 5 || counts[Error] != 10 {
+			t.Errorf("unexpected number of events per level: %v", counts)
+		}
+	})
+
+	t.Run("Tick", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithSampler(testLogger, SamplerConfig{
+			Tick:         10 * time.Millisecond,
+			SamplingRule: SamplingRule{First: 1},
+		})
+
+		logger.Info("message")
+		logger.Info("message")
+
+		time.Sleep(20 * time.Millisecond)
+
+		logger.Info("message")
+
+		if want, have := 2, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("Summary", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithSampler(testLogger, SamplerConfig{
+			Tick:            time.Hour,
+			SamplingRule:    SamplingRule{First: 1},
+			SummaryInterval: 10 * time.Millisecond,
+		})
+
+		for i := 0; i < 5; i++ {
+			logger.Info("message")
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+
+		for testLogger.Count() < 2 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+
+		events := testLogger.Events()
+
+		if want, have := 2, len(events); want != have {
+			t.Fatalf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		logEvent := LogEvent{
+			Line:   "log events dropped by sampler",
+			Level:  Warn,
+			Fields: map[string]interface{}{"dropped": 4},
+		}
+
+		logtesting.AssertLogEventsEqual(t, logEvent, events[1])
+	})
+
+	t.Run("Flush", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithSampler(testLogger, SamplerConfig{
+			Tick:            time.Hour,
+			SamplingRule:    SamplingRule{First: 1},
+			SummaryInterval: time.Hour,
+		})
+
+		logger.Info("message")
+		logger.Info("message")
+
+		logger.Flush()
+		logger.Flush()
+
+		events := testLogger.Events()
+
+		if want, have := 2, len(events); want != have {
+			t.Fatalf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		logEvent := LogEvent{
+			Line:   "log events dropped by sampler",
+			Level:  Warn,
+			Fields: map[string]interface{}{"dropped": 1},
+		}
+
+		logtesting.AssertLogEventsEqual(t, logEvent, events[1])
+	})
+
+	t.Run("SummaryLevelDisabled", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		enabler := levelEnablerFunc(func(level Level) bool { return level != Warn })
+
+		logger := WithSampler(WithLevelEnabler(testLogger, enabler), SamplerConfig{
+			Tick:            time.Hour,
+			SamplingRule:    SamplingRule{First: 1},
+			SummaryInterval: time.Hour,
+		})
+
+		logger.Info("message")
+		logger.Info("message")
+
+		logger.Flush()
+
+		if want, have := 1, testLogger.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				logger := &TestLoggerFacade{}
+
+				return WithSampler(WithMinLevel(logger, level), SamplerConfig{SamplingRule: SamplingRule{First: 1}}), logger
+			},
+		}
+
+		suite.Run(t)
+	})
+}
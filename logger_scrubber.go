@@ -0,0 +1,306 @@
+package logur
+
+import (
+	"context"
+	"regexp"
+	"sync/atomic"
+)
+
+// SecretDetector finds secrets in strings.
+type SecretDetector struct {
+	// Name identifies the detector (eg. in hit counters and the default replacement).
+	Name string
+
+	// Pattern matches candidate secrets.
+	Pattern *regexp.Regexp
+
+	// Validate optionally checks a match before it is replaced (eg. a checksum).
+	Validate func(match string) bool
+
+	// Replacement replaces detected secrets (defaults to "[REDACTED:<name>]").
+	Replacement string
+}
+
+// DefaultSecretDetectors returns the built-in secret detectors.
+//
+// The detectors are applied in order:
+// private key PEM blocks, JWTs, bearer tokens, AWS access keys, credit card numbers (Luhn validated) and emails.
+func DefaultSecretDetectors() []SecretDetector {
+	return []SecretDetector{
+		{
+			Name:    "private_key",
+			Pattern: regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`),
+		},
+		{
+			Name:    "jwt",
+			Pattern: regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+		},
+		{
+			Name:    "bearer",
+			Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+		},
+		{
+			Name:    "aws_access_key",
+			Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+		},
+		{
+			Name:     "credit_card",
+			Pattern:  regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+			Validate: luhnValid,
+		},
+		{
+			Name:    "email",
+			Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		},
+	}
+}
+
+// luhnValid checks the Luhn checksum of the digits in a string.
+func luhnValid(s string) bool {
+	var sum, digits int
+
+	double := false
+
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		digits++
+		double = !double
+	}
+
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
+
+// ScrubberLogger replaces secrets in messages and string field values before they reach a logger.
+type ScrubberLogger struct {
+	logger       LoggerFacade
+	levelEnabler LevelEnabler
+
+	detectors []SecretDetector
+	hits      []uint64
+}
+
+// NewScrubberLogger returns a new ScrubberLogger.
+// When no detectors are provided, DefaultSecretDetectors are used.
+//
+// Field values are searched recursively through nested maps (and slices), but only strings are scrubbed.
+// The original fields are never modified.
+func NewScrubberLogger(logger Logger, detectors ...SecretDetector) *ScrubberLogger {
+	if len(detectors) == 0 {
+		detectors = DefaultSecretDetectors()
+	}
+
+	l := &ScrubberLogger{
+		logger:    ensureLoggerFacade(logger),
+		detectors: make([]SecretDetector, len(detectors)),
+		hits:      make([]uint64, len(detectors)),
+	}
+
+	for i, detector := range detectors {
+		if detector.Replacement == "" {
+			detector.Replacement = "[REDACTED:" + detector.Name + "]"
+		}
+
+		l.detectors[i] = detector
+	}
+
+	if levelEnabler, ok := logger.(LevelEnabler); ok {
+		l.levelEnabler = levelEnabler
+	}
+
+	return l
+}
+
+// Hits returns the number of secrets found by each detector.
+func (l *ScrubberLogger) Hits() map[string]uint64 {
+	hits := make(map[string]uint64, len(l.detectors))
+
+	for i, detector := range l.detectors {
+		hits[detector.Name] += atomic.LoadUint64(&l.hits[i])
+	}
+
+	return hits
+}
+
+// Trace implements the Logger interface.
+func (l *ScrubberLogger) Trace(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Trace) {
+		return
+	}
+
+	l.logger.Trace(l.scrub(msg), l.scrubFields(fields)...)
+}
+
+// Debug implements the Logger interface.
+func (l *ScrubberLogger) Debug(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Debug) {
+		return
+	}
+
+	l.logger.Debug(l.scrub(msg), l.scrubFields(fields)...)
+}
+
+// Info implements the Logger interface.
+func (l *ScrubberLogger) Info(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Info) {
+		return
+	}
+
+	l.logger.Info(l.scrub(msg), l.scrubFields(fields)...)
+}
+
+// Warn implements the Logger interface.
+func (l *ScrubberLogger) Warn(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Warn) {
+		return
+	}
+
+	l.logger.Warn(l.scrub(msg), l.scrubFields(fields)...)
+}
+
+// Error implements the Logger interface.
+func (l *ScrubberLogger) Error(msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Error) {
+		return
+	}
+
+	l.logger.Error(l.scrub(msg), l.scrubFields(fields)...)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *ScrubberLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Trace) {
+		return
+	}
+
+	l.logger.TraceContext(ctx, l.scrub(msg), l.scrubFields(fields)...)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *ScrubberLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Debug) {
+		return
+	}
+
+	l.logger.DebugContext(ctx, l.scrub(msg), l.scrubFields(fields)...)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *ScrubberLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Info) {
+		return
+	}
+
+	l.logger.InfoContext(ctx, l.scrub(msg), l.scrubFields(fields)...)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *ScrubberLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Warn) {
+		return
+	}
+
+	l.logger.WarnContext(ctx, l.scrub(msg), l.scrubFields(fields)...)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *ScrubberLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if !l.LevelEnabled(Error) {
+		return
+	}
+
+	l.logger.ErrorContext(ctx, l.scrub(msg), l.scrubFields(fields)...)
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *ScrubberLogger) LevelEnabled(level Level) bool {
+	if l.levelEnabler != nil {
+		return l.levelEnabler.LevelEnabled(level)
+	}
+
+	return true
+}
+
+// scrub replaces secrets in a string.
+func (l *ScrubberLogger) scrub(s string) string {
+	for i := range l.detectors {
+		detector := &l.detectors[i]
+
+		s = detector.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if detector.Validate != nil && !detector.Validate(match) {
+				return match
+			}
+
+			atomic.AddUint64(&l.hits[i], 1)
+
+			return detector.Replacement
+		})
+	}
+
+	return s
+}
+
+func (l *ScrubberLogger) scrubFields(fields []map[string]interface{}) []map[string]interface{} {
+	if len(fields) == 0 || len(fields[0]) == 0 {
+		return fields
+	}
+
+	return []map[string]interface{}{l.scrubMap(fields[0])}
+}
+
+func (l *ScrubberLogger) scrubMap(m map[string]interface{}) map[string]interface{} {
+	scrubbed := make(map[string]interface{}, len(m))
+
+	for key, value := range m {
+		scrubbed[key] = l.scrubValue(value)
+	}
+
+	return scrubbed
+}
+
+func (l *ScrubberLogger) scrubValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return l.scrub(v)
+
+	case map[string]interface{}:
+		return l.scrubMap(v)
+
+	case Fields:
+		return Fields(l.scrubMap(v))
+
+	case []interface{}:
+		s := make([]interface{}, len(v))
+
+		for i, item := range v {
+			s[i] = l.scrubValue(item)
+		}
+
+		return s
+
+	case []string:
+		s := make([]string, len(v))
+
+		for i, item := range v {
+			s[i] = l.scrub(item)
+		}
+
+		return s
+
+	default:
+		return value
+	}
+}
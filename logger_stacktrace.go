@@ -0,0 +1,189 @@
+package logur
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StackTraceOptions configures stack trace annotation.
+type StackTraceOptions struct {
+	// Level is the minimum level of events annotated with a stack trace (defaults to Error).
+	Level *Level
+
+	// MaxDepth is the maximum number of frames in a stack trace (defaults to 32).
+	MaxDepth int
+
+	// Structured makes the "stacktrace" field a list of StackFrame values instead of a string.
+	Structured bool
+}
+
+// StackFrame is a single frame of a stack trace.
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String converts a StackFrame to string.
+func (f StackFrame) String() string {
+	return f.Function + "\n\t" + f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// WithStackTrace returns a new logger that annotates events at or above a level with the stack trace
+// of the log call as a "stacktrace" field.
+//
+// Frames of this package are trimmed from the stack trace.
+// If a field value already carries a stack trace (eg. an error with a StackTrace method, like github.com/pkg/errors),
+// that stack trace is used instead.
+//
+// The returned logger also implements LevelEnabler.
+func WithStackTrace(logger Logger, opts StackTraceOptions) LoggerFacade {
+	return WithProcessors(logger, StackTraceProcessor(opts))
+}
+
+// StackTraceProcessor returns a Processor that annotates events at or above a level with a stack trace.
+// See WithStackTrace for details.
+func StackTraceProcessor(opts StackTraceOptions) Processor {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 32
+	}
+
+	minLevel := Error
+	if opts.Level != nil {
+		minLevel = *opts.Level
+	}
+
+	return func(_ context.Context, record *Record) bool {
+		if record.Level < minLevel {
+			return true
+		}
+
+		if _, ok := record.Fields["stacktrace"]; ok {
+			return true
+		}
+
+		frames := fieldStackTrace(record.Fields, opts.MaxDepth)
+		if frames == nil {
+			frames = currentStackTrace(opts.MaxDepth)
+		}
+
+		if opts.Structured {
+			record.SetField("stacktrace", frames)
+		} else {
+			record.SetField("stacktrace", formatStackTrace(frames))
+		}
+
+		return true
+	}
+}
+
+// currentStackTrace returns the stack trace of the current goroutine without the frames of this package.
+func currentStackTrace(maxDepth int) []StackFrame {
+	pcs := make([]uintptr, maxDepth+32)
+
+	// Skip runtime.Callers and currentStackTrace
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	stack := make([]StackFrame, 0, maxDepth)
+
+	for len(stack) < maxDepth {
+		frame, more := frames.Next()
+
+		if !strings.HasPrefix(frame.Function, logurPackagePrefix) {
+			stack = append(stack, StackFrame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+// fieldStackTrace returns the stack trace carried by a field value (if any).
+func fieldStackTrace(fields map[string]interface{}, maxDepth int) []StackFrame {
+	for _, key := range sortedKeys(fields) {
+		pcs := valueStackTrace(fields[key])
+		if len(pcs) == 0 {
+			continue
+		}
+
+		frames := runtime.CallersFrames(pcs)
+		stack := make([]StackFrame, 0, len(pcs))
+
+		for len(stack) < maxDepth {
+			frame, more := frames.Next()
+
+			stack = append(stack, StackFrame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+
+			if !more {
+				break
+			}
+		}
+
+		return stack
+	}
+
+	return nil
+}
+
+// valueStackTrace returns the program counters of a value with a StackTrace method.
+//
+// StackTrace methods usually return a named slice of uintptr based frames (eg. errors.StackTrace in github.com/pkg/errors),
+// so the method is looked up using reflection to avoid depending on a specific type.
+func valueStackTrace(value interface{}) []uintptr {
+	if value == nil {
+		return nil
+	}
+
+	method := reflect.ValueOf(value).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil
+	}
+
+	methodType := method.Type()
+	if methodType.NumIn() != 0 || methodType.NumOut() != 1 {
+		return nil
+	}
+
+	if out := methodType.Out(0); out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	trace := method.Call(nil)[0]
+
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+
+	return pcs
+}
+
+// formatStackTrace formats a stack trace similar to the runtime.
+func formatStackTrace(frames []StackFrame) string {
+	var b strings.Builder
+
+	for i, frame := range frames {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		b.WriteString(frame.String())
+	}
+
+	return b.String()
+}
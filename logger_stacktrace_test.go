@@ -0,0 +1,151 @@
+package logur_test
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+// stackTrace mimics errors.StackTrace in github.com/pkg/errors.
+type stackTrace []frame
+
+type frame uintptr
+
+type stackError struct {
+	error
+
+	stack stackTrace
+}
+
+func (e stackError) StackTrace() stackTrace {
+	return e.stack
+}
+
+//go:noinline
+func newStackError(msg string) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(1, pcs)
+
+	stack := make(stackTrace, n)
+	for i, pc := range pcs[:n] {
+		stack[i] = frame(pc)
+	}
+
+	return stackError{error: errors.New(msg), stack: stack}
+}
+
+func stackTraceLevel(level Level) *Level {
+	return &level
+}
+
+func TestWithStackTrace(t *testing.T) {
+	t.Run("DefaultThreshold", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithStackTrace(WithFields(testLogger, map[string]interface{}{"key": "value"}), StackTraceOptions{})
+
+		logger.Warn("message")
+		logger.Error("message")
+
+		events := testLogger.Events()
+
+		if _, ok := events[0].Fields["stacktrace"]; ok {
+			t.Error("events below the threshold should not have a stack trace")
+		}
+
+		stack, ok := events[1].Fields["stacktrace"].(string)
+		if !ok {
+			t.Fatalf("unexpected stack trace: %#v", events[1].Fields["stacktrace"])
+		}
+
+		if want := "logur.dev/logur_test.TestWithStackTrace.func1\n\t"; !strings.HasPrefix(stack, want) {
+			t.Errorf("unexpected stack trace\nexpected prefix: %q\nactual:          %q", want, stack)
+		}
+
+		if strings.Contains(stack, "logur.dev/logur.") {
+			t.Errorf("stack trace should not contain frames of the logur package: %s", stack)
+		}
+
+		if want, have := "value", events[1].Fields["key"]; want != have {
+			t.Errorf("unexpected field value\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("Structured", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithStackTrace(testLogger, StackTraceOptions{Level: stackTraceLevel(Warn), MaxDepth: 1, Structured: true})
+
+		logger.Warn("message")
+
+		stack, ok := testLogger.LastEvent().Fields["stacktrace"].([]StackFrame)
+		if !ok {
+			t.Fatalf("unexpected stack trace: %#v", testLogger.LastEvent().Fields["stacktrace"])
+		}
+
+		if want, have := 1, len(stack); want != have {
+			t.Fatalf("unexpected stack depth\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if want, have := "logur.dev/logur_test.TestWithStackTrace.func2", stack[0].Function; want != have {
+			t.Errorf("unexpected function\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if !strings.HasSuffix(stack[0].File, "logger_stacktrace_test.go") || stack[0].Line == 0 {
+			t.Errorf("unexpected location: %s:%d", stack[0].File, stack[0].Line)
+		}
+	})
+
+	t.Run("ErrorStackTrace", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithStackTrace(testLogger, StackTraceOptions{Structured: true})
+
+		logger.Error("message", map[string]interface{}{"error": newStackError("error")})
+
+		stack := testLogger.LastEvent().Fields["stacktrace"].([]StackFrame)
+
+		if want, have := "logur.dev/logur_test.newStackError", stack[0].Function; want != have {
+			t.Errorf("unexpected function\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("ExistingField", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithStackTrace(testLogger, StackTraceOptions{})
+
+		logger.Error("message", map[string]interface{}{"stacktrace": "custom"})
+
+		if want, have := "custom", testLogger.LastEvent().Fields["stacktrace"]; want != have {
+			t.Errorf("unexpected stack trace\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				testLogger := &TestLoggerFacade{}
+
+				return WithStackTrace(WithMinLevel(testLogger, level), StackTraceOptions{Level: stackTraceLevel(Warn)}), conformance.TestLoggerFunc(
+					func() []LogEvent {
+						var events []LogEvent
+
+						for _, event := range testLogger.Events() {
+							event.Fields = copyWithout(event.Fields, "stacktrace")
+							events = append(events, event)
+						}
+
+						return events
+					},
+				)
+			},
+		}
+
+		suite.Run(t)
+	})
+}
@@ -0,0 +1,473 @@
+package logur
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SyslogFormat is the format of syslog messages.
+type SyslogFormat int
+
+// Syslog formats.
+const (
+	// SyslogRFC5424 is the format described in RFC 5424 (with structured data).
+	SyslogRFC5424 SyslogFormat = iota + 1
+
+	// SyslogRFC3164 is the legacy BSD syslog format described in RFC 3164.
+	// Fields are appended to the message in logfmt format.
+	SyslogRFC3164
+)
+
+// SyslogFacility is a syslog facility.
+type SyslogFacility int
+
+// Syslog facilities.
+const (
+	SyslogKern SyslogFacility = iota
+	SyslogUser
+	SyslogMail
+	SyslogDaemon
+	SyslogAuth
+	SyslogSyslog
+	SyslogLpr
+	SyslogNews
+	SyslogUucp
+	SyslogCron
+	SyslogAuthPriv
+	SyslogFtp
+	_
+	_
+	_
+	_
+	SyslogLocal0
+	SyslogLocal1
+	SyslogLocal2
+	SyslogLocal3
+	SyslogLocal4
+	SyslogLocal5
+	SyslogLocal6
+	SyslogLocal7
+)
+
+// Syslog severities.
+const (
+	syslogSeverityError   = 3
+	syslogSeverityWarning = 4
+	syslogSeverityInfo    = 6
+	syslogSeverityDebug   = 7
+)
+
+// syslogSeverity maps a level to a syslog severity.
+func syslogSeverity(level Level) int {
+	switch level {
+	case Error:
+		return syslogSeverityError
+
+	case Warn:
+		return syslogSeverityWarning
+
+	case Info:
+		return syslogSeverityInfo
+
+	default:
+		return syslogSeverityDebug
+	}
+}
+
+// nolint: gochecknoglobals
+var syslogLocalAddresses = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogLoggerConfig configures a SyslogLogger.
+type SyslogLoggerConfig struct {
+	// Network is one of "udp", "tcp", "tls", "unixgram" or "unix".
+	// When both Network and Address are empty, the local syslog daemon is used (eg. /dev/log).
+	Network string
+
+	// Address is a host:port pair or a socket path (for unix networks).
+	Address string
+
+	// TLSConfig is used for the "tls" network.
+	TLSConfig *tls.Config
+
+	// Format is the message format.
+	// Defaults to SyslogRFC3164 for the local syslog daemon (understood by most of them) and SyslogRFC5424 otherwise.
+	Format SyslogFormat
+
+	// Facility is the syslog facility (defaults to SyslogUser, since SyslogKern is reserved for the kernel).
+	Facility SyslogFacility
+
+	// AppName identifies the application (defaults to the name of the executable).
+	AppName string
+
+	// Hostname identifies the host (defaults to os.Hostname).
+	Hostname string
+
+	// StructuredDataID is the ID of the structured data element holding the fields of events in RFC 5424 messages.
+	// Defaults to "logur@32473" (32473 is the private enterprise number reserved for documentation).
+	StructuredDataID string
+
+	// DialTimeout limits establishing a connection (defaults to 5 seconds).
+	DialTimeout time.Duration
+
+	// WriteTimeout limits writing a message to stream connections (defaults to 5 seconds).
+	WriteTimeout time.Duration
+
+	// Clock is used to timestamp messages (defaults to the system clock).
+	Clock Clock
+
+	// ErrorHandler is called when a message cannot be sent.
+	// Errors are ignored by default.
+	ErrorHandler func(err error)
+}
+
+// SyslogLogger sends log events to a syslog daemon.
+//
+// Levels are mapped to syslog severities: Error to err, Warn to warning, Info to info, Debug and Trace to debug.
+// Broken connections are established again automatically.
+// Messages sent over unix stream sockets are new line terminated, so new lines in messages are replaced with spaces.
+//
+// The SyslogLogger is safe for concurrent use.
+type SyslogLogger struct {
+	config SyslogLoggerConfig
+	pid    string
+
+	// framing is "octet" for octet counting (RFC 6587), "newline" for new line termination
+	// and empty for message based transports
+	framing string
+
+	sink *netSink
+}
+
+// NewSyslogLogger returns a new SyslogLogger connected to a syslog daemon.
+func NewSyslogLogger(config SyslogLoggerConfig) (*SyslogLogger, error) {
+	local := config.Network == "" && config.Address == ""
+
+	if config.Format == 0 {
+		if local {
+			config.Format = SyslogRFC3164
+		} else {
+			config.Format = SyslogRFC5424
+		}
+	}
+
+	if config.Facility == SyslogKern {
+		config.Facility = SyslogUser
+	}
+
+	if config.AppName == "" {
+		config.AppName = filepath.Base(os.Args[0])
+	}
+
+	if config.Hostname == "" {
+		config.Hostname, _ = os.Hostname()
+	}
+
+	if config.StructuredDataID == "" {
+		config.StructuredDataID = "logur@32473"
+	}
+
+	if config.DialTimeout == 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+
+	if config.WriteTimeout == 0 {
+		config.WriteTimeout = 5 * time.Second
+	}
+
+	if config.Clock == nil {
+		config.Clock = SystemClock()
+	}
+
+	l := &SyslogLogger{
+		config: config,
+		pid:    strconv.Itoa(os.Getpid()),
+	}
+
+	var dial func() (net.Conn, error)
+
+	switch {
+	case local:
+		dial = func() (net.Conn, error) {
+			return dialLocalSyslog(config.DialTimeout)
+		}
+
+	case config.Network == "tls":
+		dialer := &net.Dialer{Timeout: config.DialTimeout}
+
+		dial = func() (net.Conn, error) {
+			return tls.DialWithDialer(dialer, "tcp", config.Address, config.TLSConfig)
+		}
+
+	default:
+		dial = func() (net.Conn, error) {
+			return net.DialTimeout(config.Network, config.Address, config.DialTimeout)
+		}
+	}
+
+	switch config.Network {
+	case "tcp", "tcp4", "tcp6", "tls":
+		l.framing = "octet"
+
+	case "unix":
+		l.framing = "newline"
+	}
+
+	l.sink = &netSink{
+		dial:         dial,
+		writeTimeout: config.WriteTimeout,
+	}
+
+	if err := l.sink.connect(); err != nil {
+		return nil, err
+	}
+
+	// The transport of the local syslog daemon is only known after connecting
+	if local && l.sink.conn.LocalAddr().Network() == "unix" {
+		l.framing = "newline"
+	}
+
+	return l, nil
+}
+
+// dialLocalSyslog connects to the local syslog daemon.
+func dialLocalSyslog(timeout time.Duration) (net.Conn, error) {
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, address := range syslogLocalAddresses {
+			conn, err := net.DialTimeout(network, address, timeout)
+			if err == nil {
+				return conn, nil
+			}
+		}
+	}
+
+	return nil, errors.New("syslog: no local syslog daemon found")
+}
+
+// Close closes the connection to the syslog daemon.
+func (l *SyslogLogger) Close() error {
+	return l.sink.close()
+}
+
+// Trace implements the Logger interface.
+func (l *SyslogLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *SyslogLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *SyslogLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *SyslogLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *SyslogLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *SyslogLogger) TraceContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *SyslogLogger) DebugContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *SyslogLogger) InfoContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *SyslogLogger) WarnContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *SyslogLogger) ErrorContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+func (l *SyslogLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	var f map[string]interface{}
+	if len(fields) > 0 {
+		f = fields[0]
+	}
+
+	buf := outputBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	defer outputBufferPool.Put(buf)
+
+	switch l.config.Format {
+	case SyslogRFC3164:
+		l.formatRFC3164(buf, level, msg, f)
+
+	default:
+		l.formatRFC5424(buf, level, msg, f)
+	}
+
+	message := buf.Bytes()
+
+	switch l.framing {
+	case "octet":
+		frame := make([]byte, 0, len(message)+8)
+		frame = strconv.AppendInt(frame, int64(len(message)), 10)
+		frame = append(frame, ' ')
+		message = append(frame, message...)
+
+	case "newline":
+		// A new line would split the message into multiple records
+		for i, c := range message {
+			if c == '\n' {
+				message[i] = ' '
+			}
+		}
+
+		buf.WriteByte('\n')
+		message = buf.Bytes()
+	}
+
+	if err := l.sink.write(message); err != nil && l.config.ErrorHandler != nil {
+		l.config.ErrorHandler(err)
+	}
+}
+
+func (l *SyslogLogger) priority(level Level) int {
+	return int(l.config.Facility)*8 + syslogSeverity(level)
+}
+
+// formatRFC5424 formats a message according to RFC 5424:
+// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (l *SyslogLogger) formatRFC5424(buf *bytes.Buffer, level Level, msg string, fields map[string]interface{}) {
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(l.priority(level)))
+	buf.WriteString(">1 ")
+	buf.WriteString(l.config.Clock.Now().Format("2006-01-02T15:04:05.000000Z07:00"))
+	buf.WriteByte(' ')
+	buf.WriteString(syslogHeaderField(l.config.Hostname, 255))
+	buf.WriteByte(' ')
+	buf.WriteString(syslogHeaderField(l.config.AppName, 48))
+	buf.WriteByte(' ')
+	buf.WriteString(l.pid)
+	buf.WriteString(" - ")
+
+	if len(fields) == 0 {
+		buf.WriteByte('-')
+	} else {
+		buf.WriteByte('[')
+		buf.WriteString(l.config.StructuredDataID)
+
+		for _, key := range sortedKeys(fields) {
+			buf.WriteByte(' ')
+			buf.WriteString(syslogParamName(key))
+			buf.WriteString(`="`)
+			writeSyslogParamValue(buf, logfmtValue(fields[key]))
+			buf.WriteByte('"')
+		}
+
+		buf.WriteByte(']')
+	}
+
+	if msg != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(msg)
+	}
+}
+
+// formatRFC3164 formats a message according to RFC 3164:
+// <PRI>TIMESTAMP HOSTNAME TAG[PID]: MSG
+func (l *SyslogLogger) formatRFC3164(buf *bytes.Buffer, level Level, msg string, fields map[string]interface{}) {
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(l.priority(level)))
+	buf.WriteByte('>')
+	buf.WriteString(l.config.Clock.Now().Format(time.Stamp))
+	buf.WriteByte(' ')
+	buf.WriteString(syslogHeaderField(l.config.Hostname, 255))
+	buf.WriteByte(' ')
+	buf.WriteString(syslogHeaderField(l.config.AppName, 32))
+	buf.WriteByte('[')
+	buf.WriteString(l.pid)
+	buf.WriteString("]: ")
+	buf.WriteString(msg)
+
+	for _, key := range sortedKeys(fields) {
+		buf.WriteByte(' ')
+		writeLogfmtKeyValue(buf, key, logfmtValue(fields[key]))
+	}
+}
+
+// syslogHeaderField returns a header field containing only printable US-ASCII characters (or "-" when empty).
+func syslogHeaderField(s string, maxLen int) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+
+		return r
+	}, s)
+
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+// syslogParamName returns a valid structured data parameter name:
+// 1 to 32 printable US-ASCII characters except '=', ' ', ']' and '"'.
+func syslogParamName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+
+		return r
+	}, key)
+
+	if len(name) > 32 {
+		name = name[:32]
+	}
+
+	if name == "" {
+		return "_"
+	}
+
+	return name
+}
+
+// writeSyslogParamValue writes a structured data parameter value escaping '"', '\' and ']'.
+func writeSyslogParamValue(buf *bytes.Buffer, value string) {
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '"', '\\', ']':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+
+		default:
+			buf.WriteByte(c)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package logur_test
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+)
+
+// newTestTLSConfigs returns a server and a client TLS config using a self-signed certificate.
+func newTestTLSConfigs(t *testing.T) (*tls.Config, *tls.Config) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+
+	return serverConfig, &tls.Config{RootCAs: pool}
+}
+
+// readOctetCountedFrame reads a single octet counted (RFC 6587) syslog message.
+func readOctetCountedFrame(r *bufio.Reader) (string, error) {
+	length, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, n)
+
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+
+	return string(buf), nil
+}
+
+// acceptFrames accepts connections and sends received octet counted messages to a channel.
+func acceptFrames(listener net.Listener, closeAfterFirst bool) <-chan string {
+	messages := make(chan string, 100)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				r := bufio.NewReader(conn)
+
+				for {
+					msg, err := readOctetCountedFrame(r)
+					if err != nil {
+						return
+					}
+
+					messages <- msg
+
+					if closeAfterFirst {
+						return
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return messages
+}
+
+func receive(t *testing.T, messages <-chan string) string {
+	t.Helper()
+
+	select {
+	case msg := <-messages:
+		return msg
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+
+		return ""
+	}
+}
+
+func newSyslogTestConfig(network string, address string) SyslogLoggerConfig {
+	return SyslogLoggerConfig{
+		Network:  network,
+		Address:  address,
+		Facility: SyslogLocal0,
+		AppName:  "app",
+		Hostname: "host",
+		Clock:    ClockFunc(func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC) }),
+	}
+}
+
+func TestSyslogLogger(t *testing.T) {
+	pid := os.Getpid()
+
+	t.Run("UDP", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		logger, err := NewSyslogLogger(newSyslogTestConfig("udp", conn.LocalAddr().String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Error("message", map[string]interface{}{"key": "value", "quote": `a"b]c\`, "invalid key": 1})
+
+		buf := make([]byte, 1024)
+
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := fmt.Sprintf(
+			`<131>1 2020-01-02T03:04:05.000006Z host app %d - [logur@32473 invalid_key="1" key="value" quote="a\"b\]c\\"] message`,
+			pid,
+		)
+
+		if want, have := expected, string(buf[:n]); want != have {
+			t.Errorf("unexpected message\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("TCP", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+
+		messages := acceptFrames(listener, false)
+
+		logger, err := NewSyslogLogger(newSyslogTestConfig("tcp", listener.Addr().String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info("message")
+		logger.Debug("multi\nline")
+
+		if want, have := fmt.Sprintf("<134>1 2020-01-02T03:04:05.000006Z host app %d - - message", pid), receive(t, messages); want != have {
+			t.Errorf("unexpected message\nexpected: %s\nactual:   %s", want, have)
+		}
+
+		if want, have := fmt.Sprintf("<135>1 2020-01-02T03:04:05.000006Z host app %d - - multi\nline", pid), receive(t, messages); want != have {
+			t.Errorf("unexpected message\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		serverConfig, clientConfig := newTestTLSConfigs(t)
+
+		listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+
+		messages := acceptFrames(listener, false)
+
+		config := newSyslogTestConfig("tls", listener.Addr().String())
+		config.TLSConfig = clientConfig
+		config.Format = SyslogRFC3164
+
+		logger, err := NewSyslogLogger(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Warn("message", map[string]interface{}{"key": "value with space"})
+
+		if want, have := fmt.Sprintf(`<132>Jan  2 03:04:05 host app[%d]: message key="value with space"`, pid), receive(t, messages); want != have {
+			t.Errorf("unexpected message\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("UnixNewLine", func(t *testing.T) {
+		dir, cleanup := newTempDir(t)
+		defer cleanup()
+
+		address := filepath.Join(dir, "log.sock")
+
+		listener, err := net.Listen("unix", address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+
+		lines := make(chan string, 10)
+
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			scanner := bufio.NewScanner(conn)
+
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+		}()
+
+		logger, err := NewSyslogLogger(newSyslogTestConfig("unix", address))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info("first\nsecond", map[string]interface{}{"key": "a\nb"})
+		logger.Info("third")
+
+		expected := fmt.Sprintf(`<134>1 2020-01-02T03:04:05.000006Z host app %d - [logur@32473 key="a b"] first second`, pid)
+
+		if want, have := expected, receive(t, lines); want != have {
+			t.Errorf("unexpected message\nexpected: %s\nactual:   %s", want, have)
+		}
+
+		if have := receive(t, lines); !strings.HasSuffix(have, " third") {
+			t.Errorf("unexpected message: %s", have)
+		}
+	})
+
+	t.Run("Unixgram", func(t *testing.T) {
+		dir, cleanup := newTempDir(t)
+		defer cleanup()
+
+		address := filepath.Join(dir, "log.sock")
+
+		conn, err := net.ListenPacket("unixgram", address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		logger, err := NewSyslogLogger(newSyslogTestConfig("unixgram", address))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Trace("message")
+
+		buf := make([]byte, 1024)
+
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, have := fmt.Sprintf("<135>1 2020-01-02T03:04:05.000006Z host app %d - - message", pid), string(buf[:n]); want != have {
+			t.Errorf("unexpected message\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("Reconnect", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+
+		// The server closes every connection after the first message
+		messages := acceptFrames(listener, true)
+
+		logger, err := NewSyslogLogger(newSyslogTestConfig("tcp", listener.Addr().String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logger.Close()
+
+		logger.Info("first")
+		receive(t, messages)
+
+		// Messages written to the closed connection before the failure is detected are lost
+		deadline := time.Now().Add(5 * time.Second)
+
+		for time.Now().Before(deadline) {
+			logger.Info("second")
+
+			select {
+			case msg := <-messages:
+				if !strings.HasSuffix(msg, " second") {
+					t.Errorf("unexpected message: %s", msg)
+				}
+
+				return
+
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+
+		t.Fatal("logger did not reconnect")
+	})
+
+	t.Run("ConnectionError", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		address := listener.Addr().String()
+		_ = listener.Close()
+
+		_, err = NewSyslogLogger(newSyslogTestConfig("tcp", address))
+		if err == nil {
+			t.Error("connection error is expected")
+		}
+	})
+}
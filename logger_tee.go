@@ -0,0 +1,124 @@
+package logur
+
+import (
+	"context"
+)
+
+// NewTeeLogger returns a new logger that sends every log event to all of the given loggers.
+//
+// Loggers implementing LevelEnabler only receive events they have enabled.
+// Loggers implementing LoggerContext receive the context of *Context calls.
+//
+// The returned logger also implements LevelEnabler: a level is enabled if any of the loggers enables it.
+func NewTeeLogger(loggers ...Logger) LoggerFacade {
+	l := &teeLogger{
+		loggers:       make([]LoggerFacade, 0, len(loggers)),
+		levelEnablers: make([]LevelEnabler, 0, len(loggers)),
+	}
+
+	for _, logger := range loggers {
+		levelEnabler, _ := logger.(LevelEnabler)
+
+		l.loggers = append(l.loggers, ensureLoggerFacade(logger))
+		l.levelEnablers = append(l.levelEnablers, levelEnabler)
+	}
+
+	return l
+}
+
+// teeLogger sends log events to multiple loggers.
+type teeLogger struct {
+	loggers []LoggerFacade
+
+	// levelEnablers holds the LevelEnabler of each logger (or nil if the logger does not implement it)
+	levelEnablers []LevelEnabler
+}
+
+// Trace implements the Logger interface.
+func (l *teeLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(Trace, msg, fields)
+}
+
+// Debug implements the Logger interface.
+func (l *teeLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(Debug, msg, fields)
+}
+
+// Info implements the Logger interface.
+func (l *teeLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(Info, msg, fields)
+}
+
+// Warn implements the Logger interface.
+func (l *teeLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(Warn, msg, fields)
+}
+
+// Error implements the Logger interface.
+func (l *teeLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(Error, msg, fields)
+}
+
+func (l *teeLogger) log(level Level, msg string, fields []map[string]interface{}) {
+	for i, logger := range l.loggers {
+		if !l.levelEnabledFor(i, level) {
+			continue
+		}
+
+		LevelFunc(logger, level)(msg, fields...)
+	}
+}
+
+// TraceContext implements the LoggerContext interface.
+func (l *teeLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, Trace, msg, fields)
+}
+
+// DebugContext implements the LoggerContext interface.
+func (l *teeLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, Debug, msg, fields)
+}
+
+// InfoContext implements the LoggerContext interface.
+func (l *teeLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, Info, msg, fields)
+}
+
+// WarnContext implements the LoggerContext interface.
+func (l *teeLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, Warn, msg, fields)
+}
+
+// ErrorContext implements the LoggerContext interface.
+func (l *teeLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logContext(ctx, Error, msg, fields)
+}
+
+func (l *teeLogger) logContext(ctx context.Context, level Level, msg string, fields []map[string]interface{}) {
+	for i, logger := range l.loggers {
+		if !l.levelEnabledFor(i, level) {
+			continue
+		}
+
+		LevelContextFunc(logger, level)(ctx, msg, fields...)
+	}
+}
+
+// LevelEnabled implements the LevelEnabler interface.
+func (l *teeLogger) LevelEnabled(level Level) bool {
+	for i := range l.loggers {
+		if l.levelEnabledFor(i, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *teeLogger) levelEnabledFor(i int, level Level) bool {
+	if l.levelEnablers[i] != nil {
+		return l.levelEnablers[i].LevelEnabled(level)
+	}
+
+	return true
+}
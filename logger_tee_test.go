@@ -0,0 +1,83 @@
+package logur_test
+
+import (
+	"context"
+	"testing"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+	"logur.dev/logur/logtesting"
+)
+
+type contextKey string
+
+func TestTeeLogger(t *testing.T) {
+	t.Run("LevelEnabler", func(t *testing.T) {
+		logger1 := &TestLoggerFacade{}
+		logger2 := &TestLogger{}
+
+		logger := NewTeeLogger(WithMinLevel(logger1, Warn), logger2)
+
+		logger.Info("info")
+		logger.Error("error")
+
+		if want, have := 1, logger1.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		if want, have := 2, logger2.Count(); want != have {
+			t.Errorf("unexpected number of events\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		logtesting.AssertLogEventsEqual(t, LogEvent{Line: "error", Level: Error}, *logger1.LastEvent())
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		testLogger := &TestLoggerFacade{}
+
+		logger := NewTeeLogger(
+			WithContextExtractor(testLogger, func(ctx context.Context) map[string]interface{} {
+				return map[string]interface{}{"key": ctx.Value(contextKey("key"))}
+			}),
+			&TestLogger{},
+		)
+
+		logger.InfoContext(context.WithValue(context.Background(), contextKey("key"), "value"), "message")
+
+		logEvent := LogEvent{
+			Line:   "message",
+			Level:  Info,
+			Fields: map[string]interface{}{"key": "value"},
+		}
+
+		logtesting.AssertLogEventsEqual(t, logEvent, *testLogger.LastEvent())
+	})
+
+	t.Run("LevelEnabled", func(t *testing.T) {
+		logger := NewTeeLogger(WithMinLevel(NoopLogger{}, Error), WithMinLevel(NoopLogger{}, Info)).(LevelEnabler)
+
+		if logger.LevelEnabled(Debug) {
+			t.Error("debug level should be disabled")
+		}
+
+		if !logger.LevelEnabled(Info) {
+			t.Error("info level should be enabled")
+		}
+
+		if NewTeeLogger().(LevelEnabler).LevelEnabled(Error) {
+			t.Error("an empty tee logger should not enable any levels")
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				logger := &TestLoggerFacade{}
+
+				return NewTeeLogger(WithMinLevel(logger, level), WithMinLevel(NoopLogger{}, level)), logger
+			},
+		}
+
+		suite.Run(t)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 )
 
 // LogEvent represents a single log event recorded by a test logger.
@@ -11,9 +12,15 @@ type LogEvent struct {
 	Line   string
 	Level  Level
 	Fields map[string]interface{}
+
+	// Time is the time the event was stamped with (see WithTimestamp).
+	// It is recorded from a time.Time valued field (if any) under the TimeKey of the test logger.
+	Time time.Time
 }
 
 // Equals checks if two LogEvent instances are equal.
+//
+// Times are only compared when both events have one.
 func (e LogEvent) Equals(other LogEvent) bool {
 	if e.Level != other.Level {
 		return false
@@ -23,6 +30,10 @@ func (e LogEvent) Equals(other LogEvent) bool {
 		return false
 	}
 
+	if !e.Time.IsZero() && !other.Time.IsZero() && !e.Time.Equal(other.Time) {
+		return false
+	}
+
 	if len(e.Fields) != len(other.Fields) {
 		return false
 	}
@@ -67,11 +78,17 @@ func (e logEventAssertionError) Format(s fmt.State, verb rune) {
 			_, _ = fmt.Fprintf(s, "    line:   %s\n", e.expected.Line)
 			_, _ = fmt.Fprintf(s, "    level:  %s\n", e.expected.Level)
 			_, _ = fmt.Fprintf(s, "    fields: %+v\n", e.expected.Fields)
+			if !e.expected.Time.IsZero() {
+				_, _ = fmt.Fprintf(s, "    time:   %s\n", e.expected.Time)
+			}
 
 			_, _ = fmt.Fprint(s, "actual:\n")
 			_, _ = fmt.Fprintf(s, "    line:   %s\n", e.actual.Line)
 			_, _ = fmt.Fprintf(s, "    level:  %s\n", e.actual.Level)
 			_, _ = fmt.Fprintf(s, "    fields: %+v\n", e.actual.Fields)
+			if !e.actual.Time.IsZero() {
+				_, _ = fmt.Fprintf(s, "    time:   %s\n", e.actual.Time)
+			}
 
 			return
 		}
@@ -111,6 +128,17 @@ func LogEventsEqual(expected LogEvent, actual LogEvent) error {
 	return nil
 }
 
+// eventTime returns the time an event was stamped with (if any).
+func eventTime(fields map[string]interface{}, key string) time.Time {
+	if key == "" {
+		key = "time"
+	}
+
+	t, _ := fields[key].(time.Time)
+
+	return t
+}
+
 // TestLogger is a Logger recording every log event.
 //
 // Useful when you want to test behavior with an Logger, but not with LoggerContext.
@@ -118,6 +146,10 @@ func LogEventsEqual(expected LogEvent, actual LogEvent) error {
 //
 // The TestLogger is safe for concurrent use.
 type TestLogger struct {
+	// TimeKey is the key of the field event times are recorded from (defaults to "time").
+	// Set it to the key passed to WithTimestamp.
+	TimeKey string
+
 	events []LogEvent
 	mu     sync.RWMutex
 }
@@ -180,6 +212,7 @@ func (l *TestLogger) record(level Level, msg string, varfields []map[string]inte
 		Line:   msg,
 		Level:  level,
 		Fields: fields,
+		Time:   eventTime(fields, l.TimeKey),
 	})
 }
 
@@ -215,6 +248,10 @@ func (l *TestLogger) Error(msg string, fields ...map[string]interface{}) {
 //
 // The TestLoggerContext is safe for concurrent use.
 type TestLoggerContext struct {
+	// TimeKey is the key of the field event times are recorded from (defaults to "time").
+	// Set it to the key passed to WithTimestamp.
+	TimeKey string
+
 	events []LogEvent
 	mu     sync.RWMutex
 }
@@ -270,6 +307,7 @@ func (l *TestLoggerContext) recordCtx(_ context.Context, level Level, msg string
 		Line:   msg,
 		Level:  level,
 		Fields: fields,
+		Time:   eventTime(fields, l.TimeKey),
 	})
 }
 
@@ -302,6 +340,10 @@ func (l *TestLoggerContext) ErrorContext(ctx context.Context, msg string, fields
 //
 // The TestLoggerFacade is safe for concurrent use.
 type TestLoggerFacade struct {
+	// TimeKey is the key of the field event times are recorded from (defaults to "time").
+	// Set it to the key passed to WithTimestamp.
+	TimeKey string
+
 	events []LogEvent
 	mu     sync.RWMutex
 }
@@ -357,6 +399,7 @@ func (l *TestLoggerFacade) record(level Level, msg string, varfields []map[strin
 		Line:   msg,
 		Level:  level,
 		Fields: fields,
+		Time:   eventTime(fields, l.TimeKey),
 	})
 }
 
@@ -395,6 +438,7 @@ func (l *TestLoggerFacade) recordCtx(_ context.Context, level Level, msg string,
 		Line:   msg,
 		Level:  level,
 		Fields: fields,
+		Time:   eventTime(fields, l.TimeKey),
 	})
 }
 
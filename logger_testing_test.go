@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	. "logur.dev/logur"
 	"logur.dev/logur/conformance"
@@ -48,6 +49,14 @@ func TestLogEvents_Equals(t *testing.T) {
 				Fields: Fields{"key1": "value2"},
 			},
 		},
+		"time": {
+			expected: LogEvent{
+				Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			actual: LogEvent{
+				Time: time.Date(2020, 1, 1, 0, 0, 1, 0, time.UTC),
+			},
+		},
 	}
 
 	for name, test := range tests {
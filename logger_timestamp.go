@@ -0,0 +1,68 @@
+package logur
+
+import (
+	"context"
+	"time"
+)
+
+// Clock provides the current time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// ClockFunc converts a function to a Clock.
+type ClockFunc func() time.Time
+
+// Now calls the underlying function.
+func (fn ClockFunc) Now() time.Time {
+	return fn()
+}
+
+// SystemClock returns a Clock using the system time.
+func SystemClock() Clock {
+	return ClockFunc(time.Now)
+}
+
+// WithTimestamp returns a new logger that stamps every log event with the time it is logged.
+//
+// The timestamp is added as a field with the given key (defaults to "time").
+// When layout is empty, the field holds a time.Time value, otherwise the time is formatted using the layout.
+// A time.Time value is preserved by loggers passing events around (eg. AsyncLogger)
+// and used by the built-in output loggers (eg. NewJSONLogger) when the key matches their time key.
+//
+// Events already having the field are left untouched.
+// When clock is nil, the system clock is used.
+//
+// The returned logger also implements LevelEnabler.
+func WithTimestamp(logger Logger, clock Clock, key string, layout string) LoggerFacade {
+	return WithProcessors(logger, TimestampProcessor(clock, key, layout))
+}
+
+// TimestampProcessor returns a Processor that stamps every log event with the time it is logged.
+// See WithTimestamp for details.
+func TimestampProcessor(clock Clock, key string, layout string) Processor {
+	if clock == nil {
+		clock = SystemClock()
+	}
+
+	if key == "" {
+		key = "time"
+	}
+
+	return func(_ context.Context, record *Record) bool {
+		if _, ok := record.Fields[key]; ok {
+			return true
+		}
+
+		now := clock.Now()
+
+		if layout == "" {
+			record.SetField(key, now)
+		} else {
+			record.SetField(key, now.Format(layout))
+		}
+
+		return true
+	}
+}
@@ -0,0 +1,128 @@
+package logur_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+	"logur.dev/logur/conformance"
+)
+
+// fakeClock is a Clock returning a fixed time (advanced manually).
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestWithTimestamp(t *testing.T) {
+	t.Run("Time", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithTimestamp(testLogger, clock, "", "")
+
+		logger.Info("message", map[string]interface{}{"key": "value"})
+
+		clock.now = clock.now.Add(time.Second)
+
+		logger.Info("message")
+
+		events := testLogger.Events()
+
+		if want, have := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC), events[0].Time; !want.Equal(have) {
+			t.Errorf("unexpected time\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := time.Date(2020, 1, 1, 12, 0, 1, 0, time.UTC), events[1].Fields["time"]; want != have {
+			t.Errorf("unexpected time field\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := "value", events[0].Fields["key"]; want != have {
+			t.Errorf("unexpected field value\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("CustomKey", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+		testLogger := &TestLoggerFacade{TimeKey: "ts"}
+
+		logger := WithTimestamp(testLogger, clock, "ts", "")
+
+		logger.Info("message")
+
+		if want, have := clock.now, testLogger.LastEvent().Time; !want.Equal(have) {
+			t.Errorf("unexpected time\nexpected: %v\nactual:   %v", want, have)
+		}
+	})
+
+	t.Run("Layout", func(t *testing.T) {
+		clock := ClockFunc(func() time.Time { return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC) })
+		testLogger := &TestLoggerFacade{}
+
+		logger := WithTimestamp(testLogger, clock, "ts", time.RFC3339)
+
+		logger.Info("message", map[string]interface{}{"ts": "keep"})
+		logger.Info("message")
+
+		events := testLogger.Events()
+
+		if want, have := "keep", events[0].Fields["ts"]; want != have {
+			t.Errorf("existing field should not be overridden\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := "2020-01-01T12:00:00Z", events[1].Fields["ts"]; want != have {
+			t.Errorf("unexpected time field\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if !events[1].Time.IsZero() {
+			t.Error("formatted timestamps should not be recorded as event time")
+		}
+	})
+
+	t.Run("OutputLogger", func(t *testing.T) {
+		clock := ClockFunc(func() time.Time { return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC) })
+
+		var buf bytes.Buffer
+
+		logger := WithTimestamp(NewJSONLogger(&buf), clock, "", "")
+
+		logger.Info("message")
+
+		const expected = `{"time":"2020-01-01T12:00:00Z","level":"info","msg":"message"}` + "\n"
+
+		if want, have := expected, buf.String(); want != have {
+			t.Errorf("unexpected output\nexpected: %s\nactual:   %s", want, have)
+		}
+	})
+
+	t.Run("Conformance", func(t *testing.T) {
+		suite := conformance.TestSuite{
+			LoggerFactory: func(level Level) (Logger, conformance.TestLogger) {
+				testLogger := &TestLoggerFacade{}
+
+				return WithTimestamp(WithMinLevel(testLogger, level), nil, "", ""), conformance.TestLoggerFunc(
+					func() []LogEvent {
+						var events []LogEvent
+
+						for _, event := range testLogger.Events() {
+							if event.Time.IsZero() {
+								t.Error("event time should be recorded")
+							}
+
+							event.Fields = copyWithout(event.Fields, "time")
+							events = append(events, event)
+						}
+
+						return events
+					},
+				)
+			},
+		}
+
+		suite.Run(t)
+	})
+}
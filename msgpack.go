@@ -0,0 +1,395 @@
+package logur
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// This file contains a minimal MessagePack encoder (and a decoder for the few types the Fluentd Forward protocol needs)
+// supporting the value types commonly used in log fields.
+// See https://github.com/msgpack/msgpack/blob/master/spec.md
+
+func appendMsgpackNil(b []byte) []byte {
+	return append(b, 0xc0)
+}
+
+func appendMsgpackBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0xc3)
+	}
+
+	return append(b, 0xc2)
+}
+
+func appendMsgpackInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendMsgpackUint(b, uint64(v))
+
+	case v >= -32:
+		return append(b, byte(v))
+
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+
+	case v >= math.MinInt16:
+		return append(b, 0xd1, byte(v>>8), byte(v))
+
+	case v >= math.MinInt32:
+		b = append(b, 0xd2)
+
+		return appendUint32(b, uint32(v))
+
+	default:
+		b = append(b, 0xd3)
+
+		return appendUint64(b, uint64(v))
+	}
+}
+
+func appendMsgpackUint(b []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(b, byte(v))
+
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+
+	case v <= math.MaxUint16:
+		return append(b, 0xcd, byte(v>>8), byte(v))
+
+	case v <= math.MaxUint32:
+		b = append(b, 0xce)
+
+		return appendUint32(b, uint32(v))
+
+	default:
+		b = append(b, 0xcf)
+
+		return appendUint64(b, v)
+	}
+}
+
+func appendMsgpackFloat32(b []byte, v float32) []byte {
+	b = append(b, 0xca)
+
+	return appendUint32(b, math.Float32bits(v))
+}
+
+func appendMsgpackFloat64(b []byte, v float64) []byte {
+	b = append(b, 0xcb)
+
+	return appendUint64(b, math.Float64bits(v))
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	n := len(s)
+
+	switch {
+	case n <= 31:
+		b = append(b, 0xa0|byte(n))
+
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+
+	case n <= math.MaxUint16:
+		b = append(b, 0xda, byte(n>>8), byte(n))
+
+	default:
+		b = append(b, 0xdb)
+		b = appendUint32(b, uint32(n))
+	}
+
+	return append(b, s...)
+}
+
+func appendMsgpackBinary(b []byte, v []byte) []byte {
+	n := len(v)
+
+	switch {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+
+	case n <= math.MaxUint16:
+		b = append(b, 0xc5, byte(n>>8), byte(n))
+
+	default:
+		b = append(b, 0xc6)
+		b = appendUint32(b, uint32(n))
+	}
+
+	return append(b, v...)
+}
+
+func appendMsgpackArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x90|byte(n))
+
+	case n <= math.MaxUint16:
+		return append(b, 0xdc, byte(n>>8), byte(n))
+
+	default:
+		b = append(b, 0xdd)
+
+		return appendUint32(b, uint32(n))
+	}
+}
+
+func appendMsgpackMapHeader(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x80|byte(n))
+
+	case n <= math.MaxUint16:
+		return append(b, 0xde, byte(n>>8), byte(n))
+
+	default:
+		b = append(b, 0xdf)
+
+		return appendUint32(b, uint32(n))
+	}
+}
+
+// appendMsgpackEventTime appends a time as a Fluentd EventTime (extension type 0).
+func appendMsgpackEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = appendUint32(b, uint32(t.Unix()))
+
+	return appendUint32(b, uint32(t.Nanosecond()))
+}
+
+func appendMsgpackMap(b []byte, m map[string]interface{}) []byte {
+	b = appendMsgpackMapHeader(b, len(m))
+
+	for _, key := range sortedKeys(m) {
+		b = appendMsgpackString(b, key)
+		b = appendMsgpackValue(b, m[key])
+	}
+
+	return b
+}
+
+// appendMsgpackValue appends an arbitrary value.
+// Values of unsupported types are encoded as their string representation.
+func appendMsgpackValue(b []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return appendMsgpackNil(b)
+
+	case bool:
+		return appendMsgpackBool(b, v)
+
+	case int:
+		return appendMsgpackInt(b, int64(v))
+
+	case int8:
+		return appendMsgpackInt(b, int64(v))
+
+	case int16:
+		return appendMsgpackInt(b, int64(v))
+
+	case int32:
+		return appendMsgpackInt(b, int64(v))
+
+	case int64:
+		return appendMsgpackInt(b, v)
+
+	case uint:
+		return appendMsgpackUint(b, uint64(v))
+
+	case uint8:
+		return appendMsgpackUint(b, uint64(v))
+
+	case uint16:
+		return appendMsgpackUint(b, uint64(v))
+
+	case uint32:
+		return appendMsgpackUint(b, uint64(v))
+
+	case uint64:
+		return appendMsgpackUint(b, v)
+
+	case float32:
+		return appendMsgpackFloat32(b, v)
+
+	case float64:
+		return appendMsgpackFloat64(b, v)
+
+	case string:
+		return appendMsgpackString(b, v)
+
+	case []byte:
+		return appendMsgpackBinary(b, v)
+
+	case time.Time:
+		return appendMsgpackString(b, v.Format(time.RFC3339Nano))
+
+	case time.Duration:
+		return appendMsgpackString(b, v.String())
+
+	case error:
+		return appendMsgpackString(b, v.Error())
+
+	case fmt.Stringer:
+		return appendMsgpackString(b, v.String())
+
+	case map[string]interface{}:
+		return appendMsgpackMap(b, v)
+
+	case Fields:
+		return appendMsgpackMap(b, v)
+
+	case []interface{}:
+		b = appendMsgpackArrayHeader(b, len(v))
+
+		for _, item := range v {
+			b = appendMsgpackValue(b, item)
+		}
+
+		return b
+
+	case []string:
+		b = appendMsgpackArrayHeader(b, len(v))
+
+		for _, item := range v {
+			b = appendMsgpackString(b, item)
+		}
+
+		return b
+
+	default:
+		return appendMsgpackString(b, fmt.Sprintf("%+v", v))
+	}
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+
+	binary.BigEndian.PutUint32(buf[:], v)
+
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+
+	binary.BigEndian.PutUint64(buf[:], v)
+
+	return append(b, buf[:]...)
+}
+
+// errMsgpackUnsupported is returned when decoding an unsupported type.
+var errMsgpackUnsupported = errors.New("msgpack: unsupported type")
+
+// readMsgpackStringMap reads a map with string keys and values.
+func readMsgpackStringMap(r io.ByteReader) (map[string]string, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var n int
+
+	switch {
+	case c&0xf0 == 0x80:
+		n = int(c & 0x0f)
+
+	case c == 0xde:
+		size, err := readMsgpackSize(r, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		n = size
+
+	case c == 0xdf:
+		size, err := readMsgpackSize(r, 4)
+		if err != nil {
+			return nil, err
+		}
+
+		n = size
+
+	default:
+		return nil, errMsgpackUnsupported
+	}
+
+	m := make(map[string]string, n)
+
+	for i := 0; i < n; i++ {
+		key, err := readMsgpackString(r)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := readMsgpackString(r)
+		if err != nil {
+			return nil, err
+		}
+
+		m[key] = value
+	}
+
+	return m, nil
+}
+
+func readMsgpackString(r io.ByteReader) (string, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	var n int
+
+	switch {
+	case c&0xe0 == 0xa0:
+		n = int(c & 0x1f)
+
+	case c == 0xd9:
+		n, err = readMsgpackSize(r, 1)
+
+	case c == 0xda:
+		n, err = readMsgpackSize(r, 2)
+
+	case c == 0xdb:
+		n, err = readMsgpackSize(r, 4)
+
+	default:
+		return "", errMsgpackUnsupported
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	s := make([]byte, n)
+
+	for i := range s {
+		if s[i], err = r.ReadByte(); err != nil {
+			return "", err
+		}
+	}
+
+	return string(s), nil
+}
+
+// readMsgpackSize reads a big endian unsigned integer of the given number of bytes.
+func readMsgpackSize(r io.ByteReader, bytes int) (int, error) {
+	var size int
+
+	for i := 0; i < bytes; i++ {
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		size = size<<8 | int(c)
+	}
+
+	return size, nil
+}
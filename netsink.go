@@ -0,0 +1,157 @@
+package logur
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// Reconnection backoff bounds of netSink.
+const (
+	netSinkMinBackoff = 100 * time.Millisecond
+	netSinkMaxBackoff = 10 * time.Second
+)
+
+// netSink writes messages to a network connection, reconnecting when it breaks.
+//
+// When the connection cannot be established, writes fail fast (with the last dial error)
+// until the backoff window is over, so that callers are not blocked by an unreachable peer.
+type netSink struct {
+	dial         func() (net.Conn, error)
+	writeTimeout time.Duration
+
+	// now defaults to time.Now
+	now func() time.Time
+
+	conn      net.Conn
+	downUntil time.Time
+	backoff   time.Duration
+	dialErr   error
+	mu        sync.Mutex
+}
+
+// connect dials the connection unless it's already established.
+func (s *netSink) connect() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		return nil
+	}
+
+	conn, err := s.dial()
+	if err != nil {
+		return err
+	}
+
+	s.conn = conn
+
+	return nil
+}
+
+// write writes a message to the connection.
+// When writing to an established connection fails, the connection is established again and the write is retried once.
+// The connection is dialed at most once per write.
+func (s *netSink) write(b []byte) error {
+	return s.exchange(b, nil)
+}
+
+// exchange writes a message to the connection like write,
+// then calls receive (if any) on the same connection (eg. to read a response).
+// Errors returned by receive are handled like write errors.
+func (s *netSink) exchange(b []byte, receive func(conn net.Conn) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.redial(); err != nil {
+			return err
+		}
+
+		return s.writeConn(b, receive)
+	}
+
+	if err := s.writeConn(b, receive); err == nil {
+		return nil
+	}
+
+	// Retry with a new connection
+	if err := s.redial(); err != nil {
+		return err
+	}
+
+	return s.writeConn(b, receive)
+}
+
+// redial establishes the connection unless the peer is considered down.
+func (s *netSink) redial() error {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+
+	if now().Before(s.downUntil) {
+		return s.dialErr
+	}
+
+	conn, err := s.dial()
+	if err != nil {
+		s.backoff *= 2
+		if s.backoff < netSinkMinBackoff {
+			s.backoff = netSinkMinBackoff
+		} else if s.backoff > netSinkMaxBackoff {
+			s.backoff = netSinkMaxBackoff
+		}
+
+		s.downUntil = now().Add(s.backoff)
+		s.dialErr = err
+
+		return err
+	}
+
+	s.conn = conn
+	s.backoff = 0
+	s.downUntil = time.Time{}
+	s.dialErr = nil
+
+	return nil
+}
+
+func (s *netSink) writeConn(b []byte, receive func(conn net.Conn) error) error {
+	if s.writeTimeout > 0 {
+		_ = s.conn.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+	}
+
+	_, err := s.conn.Write(b)
+	if err == nil && receive != nil {
+		err = receive(s.conn)
+	}
+
+	if err != nil {
+		s.closeConn()
+	}
+
+	return err
+}
+
+func (s *netSink) closeConn() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// close closes the connection.
+func (s *netSink) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+
+	return err
+}
@@ -0,0 +1,83 @@
+package logur
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNetSink(t *testing.T) {
+	t.Run("Down", func(t *testing.T) {
+		now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		dialErr := errors.New("connection refused")
+
+		var dials int
+
+		sink := &netSink{
+			dial: func() (net.Conn, error) {
+				dials++
+
+				return nil, dialErr
+			},
+			now: func() time.Time { return now },
+		}
+
+		for _, test := range []struct {
+			advance time.Duration
+			dials   int
+		}{
+			{0, 1},
+			{0, 1},
+			{99 * time.Millisecond, 1},
+			{time.Millisecond, 2},
+			{150 * time.Millisecond, 2},
+			{50 * time.Millisecond, 3},
+		} {
+			now = now.Add(test.advance)
+
+			if err := sink.write([]byte("message")); err != dialErr {
+				t.Errorf("unexpected error\nexpected: %v\nactual:   %v", dialErr, err)
+			}
+
+			if want, have := test.dials, dials; want != have {
+				t.Fatalf("unexpected number of dials\nexpected: %d\nactual:   %d", want, have)
+			}
+		}
+	})
+
+	t.Run("Reconnect", func(t *testing.T) {
+		var dials int
+
+		sink := &netSink{
+			dial: func() (net.Conn, error) {
+				dials++
+
+				client, server := net.Pipe()
+
+				go func() { _, _ = io.Copy(ioutil.Discard, server) }()
+
+				return client, nil
+			},
+		}
+
+		if err := sink.connect(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Break the connection
+		_ = sink.conn.Close()
+
+		if err := sink.write([]byte("message")); err != nil {
+			t.Fatal(err)
+		}
+
+		if want, have := 2, dials; want != have {
+			t.Errorf("unexpected number of dials\nexpected: %d\nactual:   %d", want, have)
+		}
+
+		_ = sink.close()
+	})
+}
@@ -1,5 +1,10 @@
 package logur
 
+import (
+	"reflect"
+	"sort"
+)
+
 // mergeFields merges some current fields with incoming log fields.
 func mergeFields(currentFields Fields, fields []map[string]interface{}) Fields {
 	if len(fields) == 0 {
@@ -23,3 +28,53 @@ func mergeFields(currentFields Fields, fields []map[string]interface{}) Fields {
 
 	return f
 }
+
+// sortedKeys returns the keys of a field map in a stable (lexical) order.
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// fieldsEqual checks if two field maps contain the same keys and values.
+func fieldsEqual(a map[string]interface{}, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, value := range a {
+		other, ok := b[key]
+		if !ok {
+			return false
+		}
+
+		// Comparable types (eg. structs) may still hold uncomparable values in interface fields,
+		// so == could panic
+		if !reflect.DeepEqual(value, other) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// copyFields returns a shallow copy of a field map.
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+
+	f := make(map[string]interface{}, len(fields))
+
+	for key, value := range fields {
+		f[key] = value
+	}
+
+	return f
+}
@@ -0,0 +1,445 @@
+package logur
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotationInterval is the interval of time based file rotation.
+type RotationInterval int
+
+// Rotation intervals.
+const (
+	// RotateNever disables time based rotation.
+	RotateNever RotationInterval = iota
+
+	// RotateHourly rotates files at the beginning of every hour.
+	RotateHourly
+
+	// RotateDaily rotates files at midnight.
+	RotateDaily
+)
+
+// backupTimeFormat is the format of the timestamp in the name of rotated files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFileConfig configures a RotatingFile.
+type RotatingFileConfig struct {
+	// Filename is the file to write to. Rotated files are kept in the same directory.
+	Filename string
+
+	// MaxSize is the maximum size of the file in bytes before it gets rotated (0 disables size based rotation).
+	MaxSize int64
+
+	// Interval enables time based rotation.
+	Interval RotationInterval
+
+	// Compress makes rotated files gzip compressed (in the background).
+	Compress bool
+
+	// MaxBackups is the maximum number of rotated files to keep (0 keeps every file).
+	MaxBackups int
+
+	// MaxAge is the maximum age of rotated files to keep (0 keeps every file).
+	MaxAge time.Duration
+
+	// FileMode is used to create new files (defaults to 0644).
+	FileMode os.FileMode
+
+	// Clock is used for time based rotation and for naming rotated files (defaults to the system clock).
+	Clock Clock
+
+	// ErrorHandler is called with errors of background work (compression and removal of old files).
+	// Errors are ignored by default.
+	ErrorHandler func(err error)
+}
+
+// RotatingFile is an io.Writer writing to a file that gets rotated by size and/or time.
+//
+// Rotated files are renamed to include the time of the rotation (eg. app-2006-01-02T15-04-05.000.log).
+// Compression and removal of old rotated files happen in the background.
+//
+// Every Write call is written to a single file, so writers writing whole lines (eg. the built-in output loggers)
+// never get their lines split between files.
+//
+// The RotatingFile is safe for concurrent use.
+type RotatingFile struct {
+	config RotatingFileConfig
+
+	file         *os.File
+	size         int64
+	nextRotation time.Time
+	closed       bool
+	mu           sync.Mutex
+
+	millCh   chan struct{}
+	millDone chan struct{}
+}
+
+// NewRotatingFile opens (or creates) a file and returns a new RotatingFile.
+func NewRotatingFile(config RotatingFileConfig) (*RotatingFile, error) {
+	if config.FileMode == 0 {
+		config.FileMode = 0644
+	}
+
+	if config.Clock == nil {
+		config.Clock = SystemClock()
+	}
+
+	f := &RotatingFile{
+		config:   config,
+		millCh:   make(chan struct{}, 1),
+		millDone: make(chan struct{}),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.Filename), 0755); err != nil {
+		return nil, err
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	f.nextRotation = f.nextRotationTime(config.Clock.Now())
+
+	go f.mill()
+
+	// Apply retention to files rotated before
+	f.triggerMill()
+
+	return f, nil
+}
+
+// Write implements the io.Writer interface.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+
+	// A previous rotation or reopen may have failed to open the file
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	now := f.config.Clock.Now()
+
+	if f.config.Interval != RotateNever && !now.Before(f.nextRotation) {
+		if err := f.rotate(now); err != nil {
+			return 0, err
+		}
+	} else if f.config.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.config.MaxSize {
+		if err := f.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Rotate rotates the file immediately.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+
+	return f.rotate(f.config.Clock.Now())
+}
+
+// Reopen closes and reopens the file (eg. after it was moved by an external tool, like logrotate, on SIGHUP).
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+
+	closeErr := f.closeFile()
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+// Close closes the file and waits for background work to finish.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+
+	if f.closed {
+		f.mu.Unlock()
+
+		return os.ErrClosed
+	}
+
+	f.closed = true
+	err := f.closeFile()
+
+	close(f.millCh)
+	f.mu.Unlock()
+
+	<-f.millDone
+
+	return err
+}
+
+// open opens the file for appending.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.config.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, f.config.FileMode)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	return nil
+}
+
+// closeFile closes the file (if it is open).
+func (f *RotatingFile) closeFile() error {
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	return err
+}
+
+// rotate renames the current file and opens a new one.
+func (f *RotatingFile) rotate(now time.Time) error {
+	if err := f.closeFile(); err != nil {
+		return err
+	}
+
+	backupTime := now.UTC()
+
+	// Make sure existing rotated files are never overwritten
+	for {
+		if _, err := os.Stat(f.backupName(backupTime)); os.IsNotExist(err) {
+			break
+		}
+
+		backupTime = backupTime.Add(time.Millisecond)
+	}
+
+	if err := os.Rename(f.config.Filename, f.backupName(backupTime)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.nextRotation = f.nextRotationTime(now)
+
+	f.triggerMill()
+
+	return nil
+}
+
+func (f *RotatingFile) nextRotationTime(now time.Time) time.Time {
+	switch f.config.Interval {
+	case RotateHourly:
+		return time.Date(now.Year(), now.Month(), now.Day(), now.Hour()+1, 0, 0, 0, now.Location())
+
+	case RotateDaily:
+		return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+
+	default:
+		return time.Time{}
+	}
+}
+
+// backupParts returns the prefix and the extension of rotated file names.
+func (f *RotatingFile) backupParts() (string, string) {
+	ext := filepath.Ext(f.config.Filename)
+
+	return strings.TrimSuffix(f.config.Filename, ext) + "-", ext
+}
+
+func (f *RotatingFile) backupName(t time.Time) string {
+	prefix, ext := f.backupParts()
+
+	return prefix + t.Format(backupTimeFormat) + ext
+}
+
+// triggerMill notifies the background goroutine (without blocking).
+func (f *RotatingFile) triggerMill() {
+	select {
+	case f.millCh <- struct{}{}:
+	default:
+	}
+}
+
+// mill removes and compresses rotated files in the background.
+func (f *RotatingFile) mill() {
+	defer close(f.millDone)
+
+	for range f.millCh {
+		if err := f.millRun(); err != nil && f.config.ErrorHandler != nil {
+			f.config.ErrorHandler(err)
+		}
+	}
+}
+
+type rotatedFile struct {
+	path       string
+	time       time.Time
+	compressed bool
+}
+
+func (f *RotatingFile) millRun() error {
+	files, err := f.rotatedFiles()
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+
+	saveErr := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	cutoff := f.config.Clock.Now().Add(-f.config.MaxAge)
+
+	for i, file := range files {
+		if (f.config.MaxBackups > 0 && i >= f.config.MaxBackups) || (f.config.MaxAge > 0 && file.time.Before(cutoff)) {
+			if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+				saveErr(err)
+			}
+
+			continue
+		}
+
+		if f.config.Compress && !file.compressed {
+			saveErr(compressFile(file.path))
+		}
+	}
+
+	return firstErr
+}
+
+// rotatedFiles returns the rotated files, newest first.
+func (f *RotatingFile) rotatedFiles() ([]rotatedFile, error) {
+	infos, err := ioutil.ReadDir(filepath.Dir(f.config.Filename))
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, ext := f.backupParts()
+	prefix = filepath.Base(prefix)
+
+	var files []rotatedFile
+
+	for _, info := range infos {
+		name := info.Name()
+
+		if info.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		file := rotatedFile{path: filepath.Join(filepath.Dir(f.config.Filename), name)}
+
+		if strings.HasSuffix(name, ext+".gz") {
+			name = strings.TrimSuffix(name, ext+".gz")
+			file.compressed = true
+		} else if strings.HasSuffix(name, ext) {
+			name = strings.TrimSuffix(name, ext)
+		} else {
+			continue
+		}
+
+		t, err := time.Parse(backupTimeFormat, strings.TrimPrefix(name, prefix))
+		if err != nil {
+			continue
+		}
+
+		file.time = t
+
+		files = append(files, file)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].time.After(files[j].time)
+	})
+
+	return files, nil
+}
+
+// compressFile gzip compresses a file and removes the original.
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(path + ".gz")
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	_ = src.Close()
+
+	return os.Remove(path)
+}
@@ -0,0 +1,384 @@
+package logur_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "logur.dev/logur"
+)
+
+// manualClock is a Clock safe for concurrent use that is advanced manually.
+type manualClock struct {
+	now time.Time
+	mu  sync.Mutex
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *manualClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newTempDir(t *testing.T) (string, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "logur")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir, func() { _ = os.RemoveAll(dir) }
+}
+
+func readDir(t *testing.T, dir string) []string {
+	t.Helper()
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(infos))
+
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(content)
+}
+
+func writeString(t *testing.T, f *RotatingFile, s string) {
+	t.Helper()
+
+	if _, err := f.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	t.Run("Size", func(t *testing.T) {
+		dir, cleanup := newTempDir(t)
+		defer cleanup()
+
+		clock := &manualClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+		f, err := NewRotatingFile(RotatingFileConfig{
+			Filename: filepath.Join(dir, "app.log"),
+			MaxSize:  10,
+			Clock:    clock,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		writeString(t, f, "line 1\n")
+		writeString(t, f, "line 2\n")
+
+		clock.Add(time.Second)
+
+		writeString(t, f, "line 3\n")
+
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"app-2020-01-01T12-00-00.000.log", "app-2020-01-01T12-00-01.000.log", "app.log"}
+
+		if want, have := expected, readDir(t, dir); strings.Join(want, ",") != strings.Join(have, ",") {
+			t.Fatalf("unexpected files\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := "line 1\n", readFile(t, filepath.Join(dir, expected[0])); want != have {
+			t.Errorf("unexpected content\nexpected: %q\nactual:   %q", want, have)
+		}
+
+		if want, have := "line 3\n", readFile(t, filepath.Join(dir, "app.log")); want != have {
+			t.Errorf("unexpected content\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		dir, cleanup := newTempDir(t)
+		defer cleanup()
+
+		clock := &manualClock{now: time.Date(2020, 1, 1, 23, 30, 0, 0, time.UTC)}
+
+		f, err := NewRotatingFile(RotatingFileConfig{
+			Filename: filepath.Join(dir, "app.log"),
+			Interval: RotateDaily,
+			Clock:    clock,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		writeString(t, f, "day 1\n")
+
+		clock.Add(20 * time.Minute)
+		writeString(t, f, "day 1\n")
+
+		clock.Add(20 * time.Minute)
+		writeString(t, f, "day 2\n")
+
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"app-2020-01-02T00-10-00.000.log", "app.log"}
+
+		if want, have := expected, readDir(t, dir); strings.Join(want, ",") != strings.Join(have, ",") {
+			t.Fatalf("unexpected files\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		if want, have := "day 1\nday 1\n", readFile(t, filepath.Join(dir, expected[0])); want != have {
+			t.Errorf("unexpected content\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		dir, cleanup := newTempDir(t)
+		defer cleanup()
+
+		clock := &manualClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+		f, err := NewRotatingFile(RotatingFileConfig{
+			Filename:   filepath.Join(dir, "app.log"),
+			Compress:   true,
+			MaxBackups: 2,
+			MaxAge:     time.Hour,
+			Clock:      clock,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 4; i++ {
+			writeString(t, f, "line\n")
+
+			if err := f.Rotate(); err != nil {
+				t.Fatal(err)
+			}
+
+			clock.Add(time.Minute)
+		}
+
+		// Let the last run remove files older than MaxAge
+		clock.Add(time.Hour - 2*time.Minute)
+
+		if err := f.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"app-2020-01-01T12-03-00.000.log.gz", "app-2020-01-01T13-02-00.000.log.gz", "app.log"}
+
+		if want, have := expected, readDir(t, dir); strings.Join(want, ",") != strings.Join(have, ",") {
+			t.Fatalf("unexpected files\nexpected: %v\nactual:   %v", want, have)
+		}
+
+		file, err := os.Open(filepath.Join(dir, expected[0]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := ioutil.ReadAll(gz)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, have := "line\n", string(content); want != have {
+			t.Errorf("unexpected content\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		dir, cleanup := newTempDir(t)
+		defer cleanup()
+
+		filename := filepath.Join(dir, "app.log")
+
+		f, err := NewRotatingFile(RotatingFileConfig{Filename: filename})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		writeString(t, f, "before\n")
+
+		if err := os.Rename(filename, filename+".1"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.Reopen(); err != nil {
+			t.Fatal(err)
+		}
+
+		writeString(t, f, "after\n")
+
+		if want, have := "before\n", readFile(t, filename+".1"); want != have {
+			t.Errorf("unexpected content\nexpected: %q\nactual:   %q", want, have)
+		}
+
+		if want, have := "after\n", readFile(t, filename); want != have {
+			t.Errorf("unexpected content\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("Recover", func(t *testing.T) {
+		dir, cleanup := newTempDir(t)
+		defer cleanup()
+
+		logDir := filepath.Join(dir, "logs")
+		filename := filepath.Join(logDir, "app.log")
+
+		if err := os.Mkdir(logDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := NewRotatingFile(RotatingFileConfig{Filename: filename})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		writeString(t, f, "before\n")
+
+		if err := os.RemoveAll(logDir); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.Rotate(); err == nil {
+			t.Fatal("rotation is expected to fail without the directory")
+		}
+
+		if err := f.Reopen(); err == nil {
+			t.Fatal("reopening is expected to fail without the directory")
+		}
+
+		if err := os.Mkdir(logDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		writeString(t, f, "write\n")
+
+		if err := f.Reopen(); err != nil {
+			t.Fatal(err)
+		}
+
+		writeString(t, f, "reopen\n")
+
+		if want, have := "write\nreopen\n", readFile(t, filename); want != have {
+			t.Errorf("unexpected content\nexpected: %q\nactual:   %q", want, have)
+		}
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		dir, cleanup := newTempDir(t)
+		defer cleanup()
+
+		f, err := NewRotatingFile(RotatingFileConfig{
+			Filename: filepath.Join(dir, "app.log"),
+			MaxSize:  1024,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logger := NewLogfmtLogger(f, TimeKey(""))
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for j := 0; j < 100; j++ {
+					logger.Info("message", map[string]interface{}{"key": "value"})
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		var lines int
+
+		for _, name := range readDir(t, dir) {
+			for _, line := range strings.SplitAfter(readFile(t, filepath.Join(dir, name)), "\n") {
+				if line == "" {
+					continue
+				}
+
+				if want, have := "level=info msg=message key=value\n", line; want != have {
+					t.Fatalf("unexpected line\nexpected: %q\nactual:   %q", want, have)
+				}
+
+				lines++
+			}
+		}
+
+		if want, have := 1000, lines; want != have {
+			t.Errorf("unexpected number of lines\nexpected: %d\nactual:   %d", want, have)
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		dir, cleanup := newTempDir(t)
+		defer cleanup()
+
+		f, err := NewRotatingFile(RotatingFileConfig{Filename: filepath.Join(dir, "app.log")})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := f.Write([]byte("message\n")); err != os.ErrClosed {
+			t.Errorf("unexpected error\nexpected: %v\nactual:   %v", os.ErrClosed, err)
+		}
+	})
+}